@@ -0,0 +1,135 @@
+package bsql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// aggregate is an aggregate function call usable as a result column, e.g.
+// "COUNT(*) AS total".
+type aggregate struct {
+	fn     string
+	expr   string
+	alias  string
+	filter Sqlizer
+}
+
+// Count renders "COUNT(expr) AS alias", or "COUNT(expr)" if alias is empty.
+// Ex:
+//     .Column(Count("*", "total"))
+func Count(expr, alias string) aggregate {
+	return aggregate{fn: "COUNT", expr: expr, alias: alias}
+}
+
+// CountDistinct renders "COUNT(DISTINCT expr) AS alias". See Count.
+//
+// To count distinct combinations of several columns, pass a row in
+// PostgreSQL, CountDistinct("(a, b)", "pairs"), or a column list in MySQL,
+// CountDistinct("a, b", "pairs").
+func CountDistinct(expr, alias string) aggregate {
+	return aggregate{fn: "COUNT", expr: "DISTINCT " + expr, alias: alias}
+}
+
+// Sum renders "SUM(expr) AS alias". See Count.
+func Sum(expr, alias string) aggregate {
+	return aggregate{fn: "SUM", expr: expr, alias: alias}
+}
+
+// Avg renders "AVG(expr) AS alias". See Count.
+func Avg(expr, alias string) aggregate {
+	return aggregate{fn: "AVG", expr: expr, alias: alias}
+}
+
+// Min renders "MIN(expr) AS alias". See Count.
+func Min(expr, alias string) aggregate {
+	return aggregate{fn: "MIN", expr: expr, alias: alias}
+}
+
+// Max renders "MAX(expr) AS alias". See Count.
+func Max(expr, alias string) aggregate {
+	return aggregate{fn: "MAX", expr: expr, alias: alias}
+}
+
+// Filter restricts the rows the aggregate is computed over to those matching
+// pred, rendering "COUNT(*) FILTER (WHERE pred) AS alias".
+// Ex:
+//     .Column(Count("*", "active").Filter(Eq{"enabled": true}))
+//
+// FILTER is supported by PostgreSQL and SQLite.
+func (a aggregate) Filter(pred Sqlizer) aggregate {
+	a.filter = pred
+	return a
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (a aggregate) ToSql() (sql string, args []interface{}, err error) {
+	sql = fmt.Sprintf("%s(%s)", a.fn, a.expr)
+	if a.filter != nil {
+		var filterSql string
+		filterSql, args, err = nestedToSql(a.filter)
+		if err != nil {
+			return
+		}
+		if filterSql != "" {
+			sql += " FILTER (WHERE " + filterSql + ")"
+		}
+	}
+	if a.alias != "" {
+		sql += " AS " + a.alias
+	}
+	return
+}
+
+// stringAgg concatenates the values of a group into a delimited string.
+type stringAgg struct {
+	flavor    flavor
+	expr      string
+	delimiter string
+	alias     string
+}
+
+// StringAgg renders an aggregate concatenating expr over each group,
+// separated by delimiter:
+//
+//   Postgres.StringAgg("name", ", ", "names")  // STRING_AGG(name, ?) AS names
+//   MySQL.StringAgg("name", ", ", "names")     // GROUP_CONCAT(name SEPARATOR ', ') AS names
+//   SQLite.StringAgg("name", ", ", "names")    // GROUP_CONCAT(name, ?) AS names
+//
+// The delimiter is bound as an arg, except for MySQL, whose SEPARATOR must be
+// a string literal. SQL Server and dialects without a flavor use STRING_AGG.
+// The alias is omitted if empty.
+func (d Dialect) StringAgg(expr, delimiter, alias string) Sqlizer {
+	return stringAgg{flavor: d.flavor, expr: expr, delimiter: delimiter, alias: alias}
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (a stringAgg) ToSql() (sql string, args []interface{}, err error) {
+	switch a.flavor {
+	case flavorMySQL:
+		sql = fmt.Sprintf("GROUP_CONCAT(%s SEPARATOR %s)", a.expr, mysqlStringLiteral(a.delimiter))
+		if a.alias != "" {
+			sql += " AS " + a.alias
+		}
+		return
+	case flavorSQLite:
+		sql = fmt.Sprintf("GROUP_CONCAT(%s, ?)", a.expr)
+	default:
+		sql = fmt.Sprintf("STRING_AGG(%s, ?)", a.expr)
+	}
+	if a.alias != "" {
+		sql += " AS " + a.alias
+	}
+	args = []interface{}{a.delimiter}
+	return
+}
+
+// mysqlStringLiteral renders s as a MySQL string literal. Strings containing
+// a ? are rendered as a hex literal, so that they are not taken for a
+// placeholder.
+func mysqlStringLiteral(s string) string {
+	if strings.Contains(s, "?") {
+		return fmt.Sprintf("X'%x'", s)
+	}
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,92 @@
+package bsql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregates(t *testing.T) {
+	b := Select("category").
+		Column(Count("*", "total")).
+		Column(Sum("qty", "")).
+		Column(Avg("price", "avg_price")).
+		Column(Min("price", "min_price")).
+		Column(Max("price", "max_price")).
+		From("products").
+		GroupBy("category")
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT category, COUNT(*) AS total, SUM(qty), AVG(price) AS avg_price, " +
+		"MIN(price) AS min_price, MAX(price) AS max_price FROM products GROUP BY category"
+	assert.Equal(t, expectedSql, sql)
+	assert.Empty(t, args)
+}
+
+func TestCountDistinct(t *testing.T) {
+	sql, args, err := Select("day").
+		Column(CountDistinct("user_id", "users")).
+		Column(CountDistinct("(user_id, device_id)", "sessions")).
+		From("visits").
+		GroupBy("day").
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT day, COUNT(DISTINCT user_id) AS users, COUNT(DISTINCT (user_id, device_id)) AS sessions FROM visits GROUP BY day", sql)
+	assert.Empty(t, args)
+}
+
+func TestStringAgg(t *testing.T) {
+	build := func(d Dialect) *SelectBuilder {
+		return Select("team").
+			Column(Case().When(Expr("size > ?", 10), "'big'").Else("'small'")).
+			Column(d.StringAgg("name", ", ", "names")).
+			From("players").
+			Where(Eq{"active": true}).
+			GroupBy("team", "size")
+	}
+
+	sql, args, err := build(Postgres).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT team, CASE WHEN size > ? THEN 'big' ELSE 'small' END, STRING_AGG(name, ?) AS names "+
+		"FROM players WHERE active = ? GROUP BY team, size", sql)
+	assert.Equal(t, []interface{}{10, ", ", true}, args)
+
+	sql, args, err = build(MySQL).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT team, CASE WHEN size > ? THEN 'big' ELSE 'small' END, GROUP_CONCAT(name SEPARATOR ', ') AS names "+
+		"FROM players WHERE active = ? GROUP BY team, size", sql)
+	assert.Equal(t, []interface{}{10, true}, args)
+
+	sql, args, err = MySQL.StringAgg("name", `it's \`, "").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `GROUP_CONCAT(name SEPARATOR 'it''s \\')`, sql)
+	assert.Empty(t, args)
+
+	sql, _, err = MySQL.StringAgg("name", " ? ", "").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "GROUP_CONCAT(name SEPARATOR X'203f20')", sql)
+
+	sql, _, err = SQLite.StringAgg("name", ";", "").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "GROUP_CONCAT(name, ?)", sql)
+}
+
+func TestAggregateFilter(t *testing.T) {
+	sql, args, err := Select("team").
+		Column(Count("*", "active").Filter(Eq{"enabled": true})).
+		Column(Sum("score", "recent_score").Filter(And{Gt{"played_at": "2024-01-01"}, NotEq{"mode": "test"}})).
+		Column(Count("*", "total")).
+		From("players").
+		Where(Eq{"league": 3}).
+		GroupBy("team").
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT team, COUNT(*) FILTER (WHERE enabled = $1) AS active, " +
+		"SUM(score) FILTER (WHERE (played_at > $2 AND mode <> $3)) AS recent_score, COUNT(*) AS total " +
+		"FROM players WHERE league = $4 GROUP BY team"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{true, "2024-01-01", "test", 3}, args)
+}
@@ -0,0 +1,49 @@
+package bsql
+
+// anyArrayEq is an Eq or NotEq rendered with PreferAnyArray: slice values
+// are compared to a single array arg.
+type anyArrayEq struct {
+	eq  Eq
+	not bool
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (e anyArrayEq) ToSql() (string, []interface{}, error) {
+	return e.eq.toSql(e.not, true)
+}
+
+// anyArrayPred returns pred with the Eq and NotEq predicates it is made of,
+// including those nested in And, Or, ConjBuilder and Not, replaced by their
+// anyArrayEq form.
+func anyArrayPred(pred Sqlizer) Sqlizer {
+	switch p := pred.(type) {
+	case *wherePart:
+		switch wp := p.pred.(type) {
+		case map[string]interface{}:
+			return &wherePart{pred: anyArrayEq{eq: Eq(wp)}}
+		case Sqlizer:
+			return &wherePart{pred: anyArrayPred(wp), args: p.args}
+		}
+	case Eq:
+		return anyArrayEq{eq: p}
+	case NotEq:
+		return anyArrayEq{eq: Eq(p), not: true}
+	case And:
+		return And(anyArrayConj(p))
+	case Or:
+		return Or(anyArrayConj(p))
+	case *ConjBuilder:
+		return &ConjBuilder{sep: p.sep, parts: anyArrayConj(p.parts)}
+	case not:
+		return not{pred: anyArrayPred(p.pred)}
+	}
+	return pred
+}
+
+func anyArrayConj(parts []Sqlizer) []Sqlizer {
+	out := make([]Sqlizer, len(parts))
+	for i, p := range parts {
+		out[i] = anyArrayPred(p)
+	}
+	return out
+}
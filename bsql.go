@@ -10,3 +10,18 @@ package bsql
 type Sqlizer interface {
 	ToSql() (string, []interface{}, error)
 }
+
+// rawSqlizer is implemented by builders that can render their SQL without
+// replacing placeholders, so that they can be nested into other statements
+// which replace placeholders once for the whole query.
+type rawSqlizer interface {
+	toSqlRaw() (string, []interface{}, error)
+}
+
+// nestedToSql builds s for inclusion in an enclosing statement.
+func nestedToSql(s Sqlizer) (string, []interface{}, error) {
+	if raw, ok := s.(rawSqlizer); ok {
+		return raw.toSqlRaw()
+	}
+	return s.ToSql()
+}
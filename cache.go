@@ -0,0 +1,123 @@
+package bsql
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// PreparerContext is the interface that wraps the PrepareContext method.
+//
+// PrepareContext prepares a statement as implemented by
+// database/sql.DB.PrepareContext. *sql.DB, *sql.Tx and *sql.Conn implement it;
+// statements prepared on a *sql.Tx are closed when it ends, so Clear the
+// CacheRunner before committing or rolling back.
+type PreparerContext interface {
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// CacheRunner is a RunnerContext that prepares each distinct query once and
+// reuses the prepared statement for later runs of the same SQL, e.g. for hot
+// queries built over and over with different args:
+//
+//   runner := NewCacheRunner(db)
+//   defer runner.Clear()
+//   Select("name").From("users").Where(Eq{"id": id}).RunWith(runner).QueryRow().Scan(&name)
+//
+// Statements are kept until Clear is called. A CacheRunner is safe for
+// concurrent use.
+type CacheRunner struct {
+	db PreparerContext
+
+	mu    sync.Mutex
+	stmts map[string]*sql.Stmt
+}
+
+// NewCacheRunner returns a CacheRunner preparing statements with db.
+func NewCacheRunner(db PreparerContext) *CacheRunner {
+	return &CacheRunner{db: db, stmts: map[string]*sql.Stmt{}}
+}
+
+// PrepareContext returns the statement prepared for query, preparing it
+// first if it is not cached yet. Queries are prepared without holding the
+// lock, so a slow prepare does not block other queries; when two goroutines
+// prepare the same query, the statement prepared last is closed.
+func (r *CacheRunner) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	r.mu.Lock()
+	stmt, ok := r.stmts[query]
+	r.mu.Unlock()
+	if ok {
+		return stmt, nil
+	}
+
+	stmt, err := r.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cached, ok := r.stmts[query]; ok {
+		stmt.Close()
+		return cached, nil
+	}
+	r.stmts[query] = stmt
+	return stmt, nil
+}
+
+// Clear closes and forgets all cached statements. It returns the first error
+// closing them, if any.
+func (r *CacheRunner) Clear() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var err error
+	for query, stmt := range r.stmts {
+		if closeErr := stmt.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+		delete(r.stmts, query)
+	}
+	return err
+}
+
+// Exec executes query with a cached statement.
+func (r *CacheRunner) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return r.ExecContext(context.Background(), query, args...)
+}
+
+// Query executes query with a cached statement.
+func (r *CacheRunner) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return r.QueryContext(context.Background(), query, args...)
+}
+
+// QueryRow executes query with a cached statement.
+func (r *CacheRunner) QueryRow(query string, args ...interface{}) RowScanner {
+	return r.QueryRowContext(context.Background(), query, args...)
+}
+
+// ExecContext executes query with a cached statement.
+func (r *CacheRunner) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	stmt, err := r.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.ExecContext(ctx, args...)
+}
+
+// QueryContext executes query with a cached statement.
+func (r *CacheRunner) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	stmt, err := r.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.QueryContext(ctx, args...)
+}
+
+// QueryRowContext executes query with a cached statement.
+func (r *CacheRunner) QueryRowContext(ctx context.Context, query string, args ...interface{}) RowScanner {
+	stmt, err := r.PrepareContext(ctx, query)
+	if err != nil {
+		return &Row{err: err}
+	}
+	return stmt.QueryRowContext(ctx, args...)
+}
@@ -0,0 +1,118 @@
+package bsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubDriver is a database/sql driver whose statements succeed without doing
+// anything, so that DBStub can return real *sql.Stmt values.
+type stubDriver struct{}
+
+func (stubDriver) Open(name string) (driver.Conn, error) { return stubConn{}, nil }
+
+type stubConn struct{}
+
+func (stubConn) Prepare(query string) (driver.Stmt, error) { return stubStmt{}, nil }
+func (stubConn) Close() error                              { return nil }
+func (stubConn) Begin() (driver.Tx, error)                 { return nil, StubError }
+
+type stubStmt struct{}
+
+func (stubStmt) Close() error                                    { return nil }
+func (stubStmt) NumInput() int                                   { return -1 }
+func (stubStmt) Exec(args []driver.Value) (driver.Result, error) { return driver.RowsAffected(1), nil }
+func (stubStmt) Query(args []driver.Value) (driver.Rows, error)  { return stubRows{}, nil }
+
+type stubRows struct{}
+
+func (stubRows) Columns() []string              { return []string{"n"} }
+func (stubRows) Close() error                   { return nil }
+func (stubRows) Next(dest []driver.Value) error { return io.EOF }
+
+var stubSqlDB = func() *sql.DB {
+	sql.Register("bsqlstub", stubDriver{})
+	db, err := sql.Open("bsqlstub", "")
+	if err != nil {
+		panic(err)
+	}
+	return db
+}()
+
+func TestCacheRunner(t *testing.T) {
+	db := &DBStub{}
+	runner := NewCacheRunner(db)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		res, err := Update("users").Set("name", "bob").Where(Eq{"id": i}).RunWith(runner).ExecContext(ctx)
+		assert.NoError(t, err)
+		n, err := res.RowsAffected()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+	}
+	assert.Equal(t, 1, db.PrepareCount)
+	assert.Equal(t, "UPDATE users SET name = ? WHERE id = ?", db.LastPrepareSql)
+
+	rows, err := Select("n").From("t").RunWith(runner).Query()
+	assert.NoError(t, err)
+	assert.NoError(t, rows.Close())
+	var n int
+	err = Select("n").From("t").RunWith(runner).QueryRow().Scan(&n)
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.Equal(t, 2, db.PrepareCount)
+
+	assert.NoError(t, runner.Clear())
+	_, err = Update("users").Set("name", "bob").Where(Eq{"id": 1}).RunWith(runner).Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, db.PrepareCount)
+}
+
+func TestCacheRunnerPrepareError(t *testing.T) {
+	runner := NewCacheRunner(&DBStub{err: StubError})
+	_, err := Delete("t").RunWith(runner).Exec()
+	assert.Equal(t, StubError, err)
+	assert.Equal(t, StubError, Select("n").From("t").RunWith(runner).QueryRow().Scan())
+}
+
+// barrierPreparer prepares statements with the stub driver once all the
+// prepares it is set up to wait for have started.
+type barrierPreparer struct {
+	started sync.WaitGroup
+}
+
+func (p *barrierPreparer) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	p.started.Done()
+	p.started.Wait()
+	return stubSqlDB.PrepareContext(ctx, query)
+}
+
+func TestCacheRunnerConcurrent(t *testing.T) {
+	db := &barrierPreparer{}
+	db.started.Add(2)
+	runner := NewCacheRunner(db)
+
+	stmts := make([]*sql.Stmt, 2)
+	var wg sync.WaitGroup
+	for i := range stmts {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			stmt, err := runner.PrepareContext(context.Background(), "DELETE FROM t")
+			assert.NoError(t, err)
+			stmts[i] = stmt
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Same(t, stmts[0], stmts[1])
+	assert.Len(t, runner.stmts, 1)
+	_, err := runner.Exec("DELETE FROM t")
+	assert.NoError(t, err)
+}
@@ -1,9 +1,6 @@
 package bsql
 
-import (
-	"bytes"
-	"errors"
-)
+import "bytes"
 
 // sqlizerBuffer is a helper that allows to write many Sqlizers one by one
 // without constant checks for errors that may come from Sqlizer
@@ -43,7 +40,17 @@ type whenPart struct {
 }
 
 func newWhenPart(when interface{}, then interface{}) whenPart {
-	return whenPart{newPart(when), newPart(then)}
+	return whenPart{newCasePart(when), newCasePart(then)}
+}
+
+// newCasePart makes a part of a CASE construct from v. Strings are taken as
+// SQL and Sqlizers are inlined; any other value is bound as a placeholder.
+func newCasePart(v interface{}) Sqlizer {
+	switch v.(type) {
+	case string, Sqlizer:
+		return newPart(v)
+	}
+	return Expr("?", v)
 }
 
 // CaseBuilder builds SQL CASE construct which could be used as parts of queries.
@@ -56,7 +63,7 @@ type CaseBuilder struct {
 // ToSql implements Sqlizer
 func (b *CaseBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 	if len(b.whenParts) == 0 {
-		err = errors.New("case expression must contain at lease one WHEN clause")
+		err = buildErrorf(CodeInvalidClause, "case expression must contain at lease one WHEN clause")
 
 		return
 	}
@@ -87,11 +94,14 @@ func (b *CaseBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 
 // what sets optional value for CASE construct "CASE [value] ..."
 func (b *CaseBuilder) what(expr interface{}) *CaseBuilder {
-	b.whatPart = newPart(expr)
+	b.whatPart = newCasePart(expr)
 	return b
 }
 
 // When adds "WHEN ... THEN ..." part to CASE construct
+//
+// Strings are used as SQL and Sqlizers (e.g. Eq) are inlined with their
+// args; other values are bound as placeholders.
 func (b *CaseBuilder) When(when interface{}, then interface{}) *CaseBuilder {
 	// TODO: performance hint: replace slice of WhenPart with just slice of parts
 	// where even indices of the slice belong to "when"s and odd indices belong to "then"s
@@ -101,7 +111,7 @@ func (b *CaseBuilder) When(when interface{}, then interface{}) *CaseBuilder {
 
 // Else sets optional "ELSE ..." part for CASE construct
 func (b *CaseBuilder) Else(expr interface{}) *CaseBuilder {
-	b.elsePart = newPart(expr)
+	b.elsePart = newCasePart(expr)
 	return b
 
 }
@@ -0,0 +1,81 @@
+package bsql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaseWithVal(t *testing.T) {
+	caseStmt := Case("number").
+		When("1", "one").
+		When("2", "two").
+		Else(Expr("?", "big number"))
+
+	qb := Select().
+		Column(caseStmt).
+		From("table")
+	sql, args, err := qb.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT CASE number " +
+		"WHEN 1 THEN one " +
+		"WHEN 2 THEN two " +
+		"ELSE ? " +
+		"END " +
+		"FROM table"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{"big number"}, args)
+}
+
+func TestCaseSearched(t *testing.T) {
+	caseStmt := Case().
+		When(Lt{"score": 50}, "fail").
+		When(Expr("score BETWEEN ? AND ?", 50, 80), 1).
+		Else(2)
+
+	qb := Select("name").
+		Column(Alias(caseStmt, "grade")).
+		From("results").
+		Where(Eq{"term": 3}).
+		PlaceholderFormat(Dollar)
+	sql, args, err := qb.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT name, (CASE " +
+		"WHEN score < $1 THEN fail " +
+		"WHEN score BETWEEN $2 AND $3 THEN $4 " +
+		"ELSE $5 " +
+		"END) AS grade " +
+		"FROM results WHERE term = $6"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{50, 50, 80, 1, 2, 3}, args)
+}
+
+func TestCaseSimpleWithArgs(t *testing.T) {
+	caseStmt := Case(Expr("status + ?", 1)).
+		When(Expr("?", 1), 10).
+		When(Expr("?", 2), 20)
+
+	sql, args, err := Select().Column(caseStmt).From("t").ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT CASE status + ? WHEN ? THEN ? WHEN ? THEN ? END FROM t"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{1, 1, 10, 2, 20}, args)
+}
+
+func TestCaseInWhere(t *testing.T) {
+	caseStmt := Case("kind").When("'a'", 1).Else(0)
+	sql, args, err := Select("id").From("t").Where(Expr("? = ?", caseStmt, 1)).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t WHERE CASE kind WHEN 'a' THEN ? ELSE ? END = ?", sql)
+	assert.Equal(t, []interface{}{1, 0, 1}, args)
+}
+
+func TestCaseWithNoWhenClause(t *testing.T) {
+	caseStmt := Case("something").Else("42")
+
+	_, _, err := Select().Column(caseStmt).From("table").ToSql()
+	assert.Error(t, err)
+}
@@ -0,0 +1,53 @@
+package bsql
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// commentReplacer neutralizes comment delimiters so that comment text cannot
+// close the comment and inject SQL, and drops ? so it is not taken for a
+// placeholder. Escaping it as ?? would leave ?? in Question queries.
+var commentReplacer = strings.NewReplacer("*/", "* /", "/*", "/ *", "?", "")
+
+// appendComment writes "/* text */ " to w, or nothing if text is empty.
+func appendComment(w io.Writer, text string) {
+	if text == "" {
+		return
+	}
+	io.WriteString(w, "/* ")
+	io.WriteString(w, commentReplacer.Replace(text))
+	io.WriteString(w, " */ ")
+}
+
+// pkgDir is the directory of this package's source files, used to tell
+// package-internal stack frames from application ones.
+var pkgDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// errNoCaller is returned by ToSql when TagCaller is set but the frame that
+// built the query cannot be found.
+var errNoCaller = errors.New("cannot tag query; caller outside of bsql not found")
+
+// callerTag returns a "/* at file.go:123 */" comment naming the first stack
+// frame outside of this package.
+func callerTag() (string, error) {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if frame.File != "" && (filepath.Dir(frame.File) != pkgDir || strings.HasSuffix(frame.File, "_test.go")) {
+			return fmt.Sprintf("/* at %s:%d */", filepath.Base(frame.File), frame.Line), nil
+		}
+		if !more {
+			return "", errNoCaller
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package bsql
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagCaller(t *testing.T) {
+	b := Select("id").From("users").Where(Eq{"id": 1}).TagCaller(true)
+	_, _, line, _ := runtime.Caller(0)
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := fmt.Sprintf("SELECT id FROM users WHERE id = ? /* at comment_test.go:%d */", line+1)
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestStatementBuilderTagCaller(t *testing.T) {
+	sb := StatementBuilder.TagCaller(true).PlaceholderFormat(Dollar)
+	sql, _, err := sb.Update("t").Set("a", 1).ToSql()
+	assert.NoError(t, err)
+	assert.Regexp(t, `^UPDATE t SET a = \$1 /\* at comment_test\.go:\d+ \*/$`, sql)
+}
+
+func TestComment(t *testing.T) {
+	sql, args, err := Select("id").Distinct().From("users").Where(Eq{"id": 1}).Comment("app=api route=users").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT /* app=api route=users */ DISTINCT id FROM users WHERE id = ?", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, _, err = Insert("t").Columns("a").Values(1).Comment("c").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT /* c */ INTO t (a) VALUES (?)", sql)
+
+	sql, _, err = Update("t").Set("a", 1).Comment("c").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE /* c */ t SET a = ?", sql)
+
+	sql, _, err = Delete("t").Comment("c").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE /* c */ FROM t", sql)
+}
+
+func TestCommentSanitized(t *testing.T) {
+	b := Select("id").From("users").
+		Where(Eq{"id": 1}).
+		Comment("x */ DROP TABLE users; /* why?").
+		PlaceholderFormat(Dollar)
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT /* x * / DROP TABLE users; / * why */ id FROM users WHERE id = $1", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, args, err = Select("id").From("users").Where(Eq{"id": 1}).Comment("why?").StrictArgs(true).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT /* why */ id FROM users WHERE id = ?", sql)
+	assert.Equal(t, []interface{}{1}, args)
+}
@@ -0,0 +1,47 @@
+package bsql
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// cte is a named subquery of a WITH clause.
+type cte struct {
+	name      string
+	columns   []string
+	sub       Sqlizer
+	recursive bool
+}
+
+// appendCtesToSql writes "WITH name[(columns)] AS (...), ... " for ctes. RECURSIVE
+// applies to the whole WITH list, so it is written once if any cte needs it.
+func appendCtesToSql(ctes []cte, w io.Writer, args []interface{}) ([]interface{}, error) {
+	io.WriteString(w, "WITH ")
+	for _, c := range ctes {
+		if c.recursive {
+			io.WriteString(w, "RECURSIVE ")
+			break
+		}
+	}
+
+	for i, c := range ctes {
+		subSql, subArgs, err := nestedToSql(c.sub)
+		if err != nil {
+			return nil, err
+		}
+
+		if i > 0 {
+			io.WriteString(w, ", ")
+		}
+		io.WriteString(w, c.name)
+		if len(c.columns) > 0 {
+			fmt.Fprintf(w, "(%s)", strings.Join(c.columns, ", "))
+		}
+		fmt.Fprintf(w, " AS (%s)", subSql)
+		args = append(args, subArgs...)
+	}
+
+	io.WriteString(w, " ")
+	return args, nil
+}
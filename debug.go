@@ -0,0 +1,131 @@
+package bsql
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// previewLen is the number of characters kept by ArgInfo.Preview.
+const previewLen = 32
+
+// ArgInfo describes a single bound argument of a query.
+type ArgInfo struct {
+	// Position is the 1-based position of the placeholder the arg is bound to.
+	Position int
+	// Type is the Go type of the arg.
+	Type string
+	// Preview is a truncated representation of the arg value. Byte slices are
+	// described by their length only.
+	Preview string
+}
+
+// Description is the SQL and a description of the args of a query, as
+// returned by Describe.
+type Description struct {
+	SQL  string
+	Args []ArgInfo
+}
+
+// Describe builds s and describes its args in placeholder order. It is meant
+// for developer tooling such as query inspectors.
+func Describe(s Sqlizer) (*Description, error) {
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Description{SQL: sql, Args: make([]ArgInfo, len(args))}
+	for i, arg := range args {
+		d.Args[i] = ArgInfo{
+			Position: i + 1,
+			Type:     fmt.Sprintf("%T", arg),
+			Preview:  preview(arg),
+		}
+	}
+	return d, nil
+}
+
+func preview(arg interface{}) string {
+	switch v := arg.(type) {
+	case nil:
+		return "NULL"
+	case []byte:
+		return fmt.Sprintf("[%d bytes]", len(v))
+	case string:
+		return fmt.Sprintf("%q", truncate(v))
+	default:
+		return truncate(fmt.Sprintf("%v", v))
+	}
+}
+
+func truncate(s string) string {
+	if utf8.RuneCountInString(s) <= previewLen {
+		return s
+	}
+	return string([]rune(s)[:previewLen]) + "..."
+}
+
+// DebugSqlizer calls ToSql on s and returns the SQL with each placeholder
+// replaced by a literal rendering of its arg. It is meant for logging and
+// troubleshooting only; never execute its output.
+//
+// Strings are single quoted with embedded quotes doubled, nil is rendered as
+// NULL and byte slices as hex literals. Escaped ?? placeholders are kept as
+// a literal ?.
+func DebugSqlizer(s Sqlizer) string {
+	sql, args, err := nestedToSql(s)
+	if err != nil {
+		return fmt.Sprintf("[ToSql error: %s]", err)
+	}
+
+	debug, err := replacePlaceholders(sql, func(buf *bytes.Buffer, i int) error {
+		if i > len(args) {
+			return fmt.Errorf("not enough args for placeholder %d", i)
+		}
+		buf.WriteString(debugLiteral(args[i-1]))
+		return nil
+	})
+	if err != nil {
+		return fmt.Sprintf("[DebugSqlizer error: %s]", err)
+	}
+	return debug
+}
+
+func debugLiteral(arg interface{}) string {
+	if v, ok := arg.(driver.Valuer); ok {
+		val, err := v.Value()
+		if err != nil {
+			return fmt.Sprintf("[Value error: %s]", err)
+		}
+		arg = val
+	}
+
+	switch v := arg.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return quoteLiteral(v)
+	case []byte:
+		return "X'" + hex.EncodeToString(v) + "'"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprintf("%v", v)
+	case time.Time:
+		return quoteLiteral(v.Format(time.RFC3339Nano))
+	default:
+		return quoteLiteral(fmt.Sprintf("%v", v))
+	}
+}
+
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
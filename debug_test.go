@@ -0,0 +1,44 @@
+package bsql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribe(t *testing.T) {
+	long := strings.Repeat("x", 40)
+	b := Select("*").From("files").
+		Where(Eq{"name": long}).
+		Where("data = ?", []byte("secret")).
+		Where(Eq{"size": 10, "owner": nil}).
+		PlaceholderFormat(Dollar)
+	d, err := Describe(b)
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT * FROM files WHERE name = $1 AND data = $2 AND owner IS NULL AND size = $3"
+	assert.Equal(t, expectedSql, d.SQL)
+
+	expectedArgs := []ArgInfo{
+		{Position: 1, Type: "string", Preview: `"` + strings.Repeat("x", 32) + `..."`},
+		{Position: 2, Type: "[]uint8", Preview: "[6 bytes]"},
+		{Position: 3, Type: "int", Preview: "10"},
+	}
+	assert.Equal(t, expectedArgs, d.Args)
+}
+
+func TestDebugSqlizer(t *testing.T) {
+	b := Select("*").From("users").
+		Where(Eq{"name": "O'Brien", "deleted_at": nil}).
+		Where("tags ?? 'vip' AND age > ? AND active = ?", 30, true).
+		Where("token = ?", []byte{0xde, 0xad}).
+		PlaceholderFormat(Dollar)
+
+	expectedSql := "SELECT * FROM users WHERE deleted_at IS NULL AND name = 'O''Brien' " +
+		"AND tags ? 'vip' AND age > 30 AND active = TRUE AND token = X'dead'"
+	assert.Equal(t, expectedSql, DebugSqlizer(b))
+
+	assert.Equal(t, "a = NULL", DebugSqlizer(Expr("a = ?", nil)))
+	assert.Equal(t, "[DebugSqlizer error: not enough args for placeholder 2]", DebugSqlizer(Expr("a = ? AND b = ?", 1)))
+}
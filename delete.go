@@ -2,7 +2,9 @@ package bsql
 
 import (
 	"bytes"
-	"fmt"
+	"context"
+	"database/sql"
+	"errors"
 	"strconv"
 	"strings"
 )
@@ -15,6 +17,7 @@ type DeleteBuilder struct {
 
 	returning
 
+	comment    string
 	prefixes   exprs
 	what       []string
 	from       string
@@ -36,6 +39,34 @@ func NewDeleteBuilder(b StatementBuilderType) *DeleteBuilder {
 	return &DeleteBuilder{StatementBuilderType: b}
 }
 
+// Clone returns a copy of b that can be changed without affecting b.
+//
+// See SelectBuilder.Clone.
+func (b *DeleteBuilder) Clone() *DeleteBuilder {
+	c := *b
+	c.returning = c.returning[:len(c.returning):len(c.returning)]
+	c.prefixes = c.prefixes[:len(c.prefixes):len(c.prefixes)]
+	c.what = c.what[:len(c.what):len(c.what)]
+	c.joins = c.joins[:len(c.joins):len(c.joins)]
+	c.usingParts = c.usingParts[:len(c.usingParts):len(c.usingParts)]
+	c.whereParts = c.whereParts[:len(c.whereParts):len(c.whereParts)]
+	c.orderBys = c.orderBys[:len(c.orderBys):len(c.orderBys)]
+	c.suffixes = c.suffixes[:len(c.suffixes):len(c.suffixes)]
+	return &c
+}
+
+// ForTable returns a copy of the query deleting from table instead.
+//
+// See SelectBuilder.ForTable.
+func (b *DeleteBuilder) ForTable(table string) *DeleteBuilder {
+	c := b.Clone()
+	if len(c.what) == 1 && c.what[0] == c.from {
+		c.what = []string{table}
+	}
+	c.from = table
+	return c
+}
+
 // PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
 // query.
 func (b *DeleteBuilder) PlaceholderFormat(f PlaceholderFormat) *DeleteBuilder {
@@ -43,21 +74,151 @@ func (b *DeleteBuilder) PlaceholderFormat(f PlaceholderFormat) *DeleteBuilder {
 	return b
 }
 
+// TagCaller makes ToSql append a "/* at file.go:123 */" comment naming the
+// code outside of bsql that built the query, to trace queries seen in
+// database logs back to their source. ToSql fails if no such caller is found.
+func (b *DeleteBuilder) TagCaller(tag bool) *DeleteBuilder {
+	b.tagCaller = tag
+	return b
+}
+
+// PlaceholderOffset makes positional placeholders start at n+1.
+//
+// See SelectBuilder.PlaceholderOffset.
+func (b *DeleteBuilder) PlaceholderOffset(n int) *DeleteBuilder {
+	b.placeholderOffset = n
+	return b
+}
+
+// StrictArgs makes ToSql fail when the number of placeholders in the query
+// does not match the number of args.
+//
+// See SelectBuilder.StrictArgs.
+func (b *DeleteBuilder) StrictArgs(strict bool) *DeleteBuilder {
+	b.strictArgs = strict
+	return b
+}
+
+// PreEvalValuers makes ToSql replace driver.Valuer args by their value.
+//
+// See SelectBuilder.PreEvalValuers.
+func (b *DeleteBuilder) PreEvalValuers(eval bool) *DeleteBuilder {
+	b.preEvalValuers = eval
+	return b
+}
+
+// NormalizeNils makes ToSql replace nil pointer args by nil.
+//
+// See SelectBuilder.NormalizeNils.
+func (b *DeleteBuilder) NormalizeNils(normalize bool) *DeleteBuilder {
+	b.normalizeNils = normalize
+	return b
+}
+
+// PreferAnyArray makes Eq slices in Where render as "col = ANY(?)" with one
+// array arg.
+//
+// See SelectBuilder.PreferAnyArray.
+func (b *DeleteBuilder) PreferAnyArray(prefer bool) *DeleteBuilder {
+	b.preferAnyArray = prefer
+	return b
+}
+
+// BoolAsInt makes ToSql bind bool args as 1 and 0.
+//
+// See SelectBuilder.BoolAsInt.
+func (b *DeleteBuilder) BoolAsInt(asInt bool) *DeleteBuilder {
+	b.boolAsInt = asInt
+	return b
+}
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+//
+// *sql.DB and *sql.Tx are wrapped automatically.
+func (b *DeleteBuilder) RunWith(runner BaseRunner) *DeleteBuilder {
+	b.runner = wrapRunner(runner)
+	return b
+}
+
+// Exec builds and executes the query with the Runner set by RunWith.
+func (b *DeleteBuilder) Exec() (sql.Result, error) {
+	return exec(b.runner, b)
+}
+
+// Query builds and executes the query with the Runner set by RunWith.
+func (b *DeleteBuilder) Query() (*sql.Rows, error) {
+	return query(b.runner, b)
+}
+
+// QueryRow builds and executes the query with the Runner set by RunWith.
+// The Runner must implement QueryRower.
+func (b *DeleteBuilder) QueryRow() RowScanner {
+	return queryRow(b.runner, b)
+}
+
+// ExecContext builds and executes the query with the Runner set by RunWith.
+// The Runner must implement ExecerContext.
+func (b *DeleteBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
+	return execContext(ctx, b.runner, b)
+}
+
+// QueryContext builds and executes the query with the Runner set by RunWith.
+// The Runner must implement QueryerContext.
+func (b *DeleteBuilder) QueryContext(ctx context.Context) (*sql.Rows, error) {
+	return queryContext(ctx, b.runner, b)
+}
+
+// QueryRowContext builds and executes the query with the Runner set by
+// RunWith. The Runner must implement QueryRowerContext.
+func (b *DeleteBuilder) QueryRowContext(ctx context.Context) RowScanner {
+	return queryRowContext(ctx, b.runner, b)
+}
+
+// ExecReturning executes the query with the Runner set by RunWith and scans
+// the row produced by its RETURNING clause into dest. It returns
+// ErrNoReturning if Returning was not called.
+//
+// See InsertBuilder.ExecReturning.
+func (b *DeleteBuilder) ExecReturning(ctx context.Context, dest ...interface{}) error {
+	return b.returning.execReturning(ctx, b.runner, b, dest...)
+}
+
+// validate reports all structural problems of the query at once.
+func (b *DeleteBuilder) validate() error {
+	var errs []error
+	if len(b.from) == 0 {
+		errs = append(errs, buildErrorf(CodeNoTable, "delete statements must specify a From table"))
+	}
+	if len(b.usingParts) > 0 && len(b.joins) > 0 {
+		errs = append(errs, buildErrorf(CodeInvalidClause, "delete statements cannot have both Using and Join clauses"))
+	}
+	if (b.limitValid || b.offsetValid) && b.flavor == flavorSQLServer {
+		errs = append(errs, buildErrorf(CodeInvalidClause, "delete statements cannot have LIMIT or OFFSET under SQL Server"))
+	}
+	if err := b.checkPreferAnyArray(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
 // ToSql builds the query into a SQL string and bound args.
 func (b *DeleteBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
-	if len(b.from) == 0 {
-		err = fmt.Errorf("delete statements must specify a From table")
+	if err = b.validate(); err != nil {
 		return
 	}
 
 	sql := &bytes.Buffer{}
 
 	if len(b.prefixes) > 0 {
-		args, _ = b.prefixes.AppendToSql(sql, " ", args)
+		args, err = b.prefixes.AppendToSql(sql, " ", args)
+		if err != nil {
+			return
+		}
 		sql.WriteString(" ")
 	}
 
 	sql.WriteString("DELETE ")
+	appendComment(sql, b.comment)
 	// following condition helps to avoid duplicate "from" value in DELETE query
 	// e.g. "DELETE a FROM a ..." which is valid for MySQL but not for PostgreSQL
 	if len(b.what) > 0 && (len(b.what) != 1 || b.what[0] != b.from) {
@@ -74,16 +235,14 @@ func (b *DeleteBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 	}
 
 	if len(b.usingParts) > 0 {
-		sql.WriteString(" USING ")
-		args, err = appendToSql(b.usingParts, sql, ", ", args)
+		args, err = appendClauseToSql(b.usingParts, sql, " USING ", ", ", args)
 		if err != nil {
 			return
 		}
 	}
 
 	if len(b.whereParts) > 0 {
-		sql.WriteString(" WHERE ")
-		args, err = appendToSql(b.whereParts, sql, " AND ", args)
+		args, err = appendClauseToSql(b.predicates(b.whereParts), sql, " WHERE ", " AND ", args)
 		if err != nil {
 			return
 		}
@@ -94,7 +253,6 @@ func (b *DeleteBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 		sql.WriteString(strings.Join(b.orderBys, ", "))
 	}
 
-	// TODO: limit == 0 and offswt == 0 are valid. Need to go dbr way and implement offsetValid and limitValid
 	if b.limitValid {
 		sql.WriteString(" LIMIT ")
 		sql.WriteString(strconv.FormatUint(b.limit, 10))
@@ -114,13 +272,23 @@ func (b *DeleteBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 
 	if len(b.suffixes) > 0 {
 		sql.WriteString(" ")
-		args, _ = b.suffixes.AppendToSql(sql, " ", args)
+		args, err = b.suffixes.AppendToSql(sql, " ", args)
+		if err != nil {
+			return
+		}
 	}
 
-	sqlStr, err = b.placeholderFormat.ReplacePlaceholders(sql.String())
+	sqlStr, args, err = b.finalize(sql.String(), args)
 	return
 }
 
+// Comment adds a "/* text */" comment right after the DELETE keyword, e.g. to
+// tag queries for monitoring. Comment delimiters in text are neutralized.
+func (b *DeleteBuilder) Comment(text string) *DeleteBuilder {
+	b.comment = text
+	return b
+}
+
 // Prefix adds an expression to the beginning of the query
 func (b *DeleteBuilder) Prefix(sql string, args ...interface{}) *DeleteBuilder {
 	b.prefixes = append(b.prefixes, Expr(sql, args...))
@@ -177,12 +345,30 @@ func (b *DeleteBuilder) Where(pred interface{}, args ...interface{}) *DeleteBuil
 	return b
 }
 
+// WhereIf adds a WHERE expression to the query when cond is true and is
+// a no-op otherwise.
+func (b *DeleteBuilder) WhereIf(cond bool, pred interface{}, args ...interface{}) *DeleteBuilder {
+	if !cond {
+		return b
+	}
+	return b.Where(pred, args...)
+}
+
 // OrderBy adds ORDER BY expressions to the query.
 func (b *DeleteBuilder) OrderBy(orderBys ...string) *DeleteBuilder {
 	b.orderBys = append(b.orderBys, orderBys...)
 	return b
 }
 
+// OrderByIf adds ORDER BY expressions to the query when cond is true and is
+// a no-op otherwise.
+func (b *DeleteBuilder) OrderByIf(cond bool, orderBys ...string) *DeleteBuilder {
+	if !cond {
+		return b
+	}
+	return b.OrderBy(orderBys...)
+}
+
 // Limit sets a LIMIT clause on the query.
 func (b *DeleteBuilder) Limit(limit uint64) *DeleteBuilder {
 	b.limit = limit
@@ -190,6 +376,15 @@ func (b *DeleteBuilder) Limit(limit uint64) *DeleteBuilder {
 	return b
 }
 
+// LimitIf adds a LIMIT clause to the query when cond is true and is
+// a no-op otherwise.
+func (b *DeleteBuilder) LimitIf(cond bool, limit uint64) *DeleteBuilder {
+	if !cond {
+		return b
+	}
+	return b.Limit(limit)
+}
+
 // Offset sets a OFFSET clause on the query.
 func (b *DeleteBuilder) Offset(offset uint64) *DeleteBuilder {
 	b.offset = offset
@@ -197,6 +392,21 @@ func (b *DeleteBuilder) Offset(offset uint64) *DeleteBuilder {
 	return b
 }
 
+// ClearLimit removes the LIMIT clause set by Limit. Unlike Limit(0), which
+// renders "LIMIT 0", it leaves the query without a LIMIT clause.
+func (b *DeleteBuilder) ClearLimit() *DeleteBuilder {
+	b.limit = 0
+	b.limitValid = false
+	return b
+}
+
+// ClearOffset removes the OFFSET clause set by Offset.
+func (b *DeleteBuilder) ClearOffset() *DeleteBuilder {
+	b.offset = 0
+	b.offsetValid = false
+	return b
+}
+
 // Returning adds columns to RETURNING clause of the query
 //
 // DELETE ... RETURNING is PostgreSQL specific extension
@@ -205,6 +415,16 @@ func (b *DeleteBuilder) Returning(columns ...string) *DeleteBuilder {
 	return b
 }
 
+// ReturningAll sets a RETURNING * clause, returning all columns of the
+// affected rows. It replaces the columns added by Returning and
+// ReturningSelect, and is replaced by the ones added after it.
+//
+// DELETE ... RETURNING is PostgreSQL specific extension
+func (b *DeleteBuilder) ReturningAll() *DeleteBuilder {
+	b.returning.ReturningAll()
+	return b
+}
+
 // ReturningSelect adds subquery to RETURNING clause of the query
 //
 // DELETE ... RETURNING is PostgreSQL specific extension
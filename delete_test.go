@@ -0,0 +1,124 @@
+package bsql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteBuilderLimit(t *testing.T) {
+	sql, args, err := Delete("t").Where(Eq{"b": 2}).Limit(1000).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM t WHERE b = ? LIMIT 1000", sql)
+	assert.Equal(t, []interface{}{2}, args)
+
+	sql, _, err = Delete("t").Limit(0).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM t LIMIT 0", sql)
+}
+
+func TestDeleteBuilderRunContext(t *testing.T) {
+	db := &DBStub{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "delete")
+	b := Delete("t").Where(Eq{"a": 1}).Returning("id").RunWith(db)
+
+	_, err := b.QueryContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, ctx, db.LastContext)
+	assert.Equal(t, "DELETE FROM t WHERE a = ? RETURNING id", db.LastQuerySql)
+	assert.Equal(t, []interface{}{1}, db.LastQueryArgs)
+}
+
+func TestDeleteBuilderForTable(t *testing.T) {
+	sql, _, err := Delete("events_0").Where(Eq{"a": 1}).ForTable("events_1").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM events_1 WHERE a = ?", sql)
+}
+
+func TestDeleteBuilderUsing(t *testing.T) {
+	sql, args, err := Delete("a").Using("b").Where("a.bid = b.id AND b.state = ?", "gone").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM a USING b WHERE a.bid = b.id AND b.state = ?", sql)
+	assert.Equal(t, []interface{}{"gone"}, args)
+}
+
+func TestDeleteBuilderUsingSelect(t *testing.T) {
+	stale := Select("id").From("sessions").Where(Lt{"seen_at": 100})
+	b := Delete("tokens").
+		UsingSelect(stale, "s").
+		Where("tokens.session_id = s.id AND tokens.kind = ?", "refresh").
+		PlaceholderFormat(Dollar)
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "DELETE FROM tokens USING (SELECT id FROM sessions WHERE seen_at < $1) AS s " +
+		"WHERE tokens.session_id = s.id AND tokens.kind = $2"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{100, "refresh"}, args)
+}
+
+func TestDeleteBuilderExecReturning(t *testing.T) {
+	db := &DBStub{row: []interface{}{int64(4), "bob"}}
+	var (
+		id   int64
+		name string
+	)
+	err := Delete("users").Where(Eq{"id": 4}).Returning("id", "name").
+		RunWith(db).ExecReturning(context.Background(), &id, &name)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), id)
+	assert.Equal(t, "bob", name)
+	assert.Equal(t, "DELETE FROM users WHERE id = ? RETURNING id, name", db.LastQueryRowSql)
+
+	err = Delete("users").RunWith(db).ExecReturning(context.Background(), &id)
+	assert.Equal(t, ErrNoReturning, err)
+}
+
+func TestDeleteBuilderValidationErrors(t *testing.T) {
+	_, _, err := Delete("").Using("b").JoinClause("JOIN c ON c.id = b.cid").ToSql()
+	assert.EqualError(t, err, "delete statements must specify a From table\n"+
+		"delete statements cannot have both Using and Join clauses")
+}
+
+func TestDeleteBuilderReturningAll(t *testing.T) {
+	sql, _, err := Delete("users").Where(Eq{"id": 1}).ReturningAll().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM users WHERE id = ? RETURNING *", sql)
+
+	sql, _, err = Delete("users").Returning("id").ReturningAll().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM users RETURNING *", sql)
+
+	sql, _, err = Delete("users").ReturningAll().Returning("id", "name").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM users RETURNING id, name", sql)
+}
+
+func TestDeleteBuilderWhereIf(t *testing.T) {
+	sql, args, err := Delete("sessions").
+		WhereIf(false, Eq{"user_id": 1}).
+		OrderByIf(true, "created_at").
+		LimitIf(false, 10).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM sessions ORDER BY created_at", sql)
+	assert.Empty(t, args)
+}
+
+func TestDeleteBuilderClearLimit(t *testing.T) {
+	sql, _, err := Delete("t").Limit(0).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM t LIMIT 0", sql)
+
+	sql, _, err = Delete("t").Limit(10).Offset(5).ClearLimit().ClearOffset().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM t", sql)
+}
+
+func TestDeleteBuilderOrderByLimit(t *testing.T) {
+	sql, args, err := Delete("t").Where(Eq{"x": 1}).OrderBy("created_at").Limit(100).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM t WHERE x = ? ORDER BY created_at LIMIT 100", sql)
+	assert.Equal(t, []interface{}{1}, args)
+}
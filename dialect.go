@@ -0,0 +1,48 @@
+package bsql
+
+// flavor identifies the database of a Dialect for SQL that differs beyond
+// placeholders and quoting.
+type flavor int
+
+const (
+	flavorDefault flavor = iota
+	flavorPostgres
+	flavorMySQL
+	flavorSQLServer
+	flavorSQLite
+)
+
+// Dialect bundles the settings that differ between databases. Set it on a
+// StatementBuilderType to configure all child builders at once:
+//
+//   sb := StatementBuilder.Dialect(Postgres)
+//   sb.Select("id", "COUNT(*)").From("users").Where(Eq{"id": 1})
+//   // SELECT "id", COUNT(*) FROM users WHERE id = $1
+//
+// The predefined dialects quote the plain identifiers given to Columns,
+// GroupBy, OrderBy and Insert, leaving expressions as is; copy one and clear
+// Quoting to disable that.
+type Dialect struct {
+	// PlaceholderFormat is the placeholder format of the database.
+	PlaceholderFormat PlaceholderFormat
+	// Quoting is the identifier QuoteStyle of the database.
+	Quoting QuoteStyle
+
+	flavor flavor
+}
+
+var (
+	// Postgres is the PostgreSQL dialect: $1 placeholders and "ident" quoting.
+	Postgres = Dialect{PlaceholderFormat: Dollar, Quoting: ANSIQuotes, flavor: flavorPostgres}
+
+	// MySQL is the MySQL dialect: ? placeholders and `ident` quoting.
+	MySQL = Dialect{PlaceholderFormat: Question, Quoting: MySQLQuotes, flavor: flavorMySQL}
+
+	// SQLServer is the SQL Server dialect: @p1 placeholders, [ident] quoting,
+	// and SELECT TOP or OFFSET ... FETCH in place of LIMIT and OFFSET. Update
+	// and Delete statements cannot have a Limit.
+	SQLServer = Dialect{PlaceholderFormat: AtP, Quoting: BracketQuotes, flavor: flavorSQLServer}
+
+	// SQLite is the SQLite dialect: ? placeholders and "ident" quoting.
+	SQLite = Dialect{PlaceholderFormat: Question, Quoting: ANSIQuotes, flavor: flavorSQLite}
+)
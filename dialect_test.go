@@ -0,0 +1,72 @@
+package bsql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialects(t *testing.T) {
+	build := func(d Dialect) *SelectBuilder {
+		return StatementBuilder.Dialect(d).
+			Select("id", "name").
+			From("users").
+			Where(Eq{"team": 1}).
+			Where("age > ?", 18).
+			OrderBy("name").
+			Limit(10)
+	}
+
+	tests := []struct {
+		dialect     Dialect
+		expectedSql string
+	}{
+		{Postgres, `SELECT "id", "name" FROM users WHERE team = $1 AND age > $2 ORDER BY "name" LIMIT 10`},
+		{MySQL, "SELECT `id`, `name` FROM users WHERE team = ? AND age > ? ORDER BY `name` LIMIT 10"},
+		{SQLServer, "SELECT TOP 10 [id], [name] FROM users WHERE team = @p1 AND age > @p2 ORDER BY [name]"},
+	}
+	for _, test := range tests {
+		sql, args, err := build(test.dialect).ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, test.expectedSql, sql)
+		assert.Equal(t, []interface{}{1, 18}, args)
+	}
+}
+
+func TestSQLServerOffsetFetch(t *testing.T) {
+	sb := StatementBuilder.Dialect(SQLServer)
+
+	sql, _, err := sb.Select("id").Distinct().From("users").OrderBy("id").Limit(10).Offset(20).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT DISTINCT [id] FROM users ORDER BY [id] OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY", sql)
+
+	sql, _, err = sb.Select("id").Distinct().From("users").Limit(5).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT DISTINCT TOP 5 [id] FROM users", sql)
+}
+
+func TestDialectQuoting(t *testing.T) {
+	sql, _, err := StatementBuilder.Dialect(Postgres).Select("id", "COUNT(*)").From("users").GroupBy("id").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT "id", COUNT(*) FROM users GROUP BY "id"`, sql)
+
+	d := Postgres
+	d.Quoting = QuoteStyle{}
+	sql, _, err = StatementBuilder.Dialect(d).Select("id").From("users").Where(Eq{"a": 1}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE a = $1", sql)
+}
+
+func TestSQLServerUpdateDeleteLimit(t *testing.T) {
+	sb := StatementBuilder.Dialect(SQLServer)
+
+	_, _, err := sb.Update("users").Set("active", false).Limit(10).ToSql()
+	assert.ErrorIs(t, err, ErrInvalidClause)
+
+	_, _, err = sb.Delete("users").Where(Eq{"active": false}).Limit(10).ToSql()
+	assert.ErrorIs(t, err, ErrInvalidClause)
+
+	sql, _, err := sb.Delete("users").Where(Eq{"active": false}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM users WHERE active = @p1", sql)
+}
@@ -0,0 +1,66 @@
+package bsql
+
+import "fmt"
+
+// ErrorCode classifies a BuildError.
+type ErrorCode int
+
+const (
+	// CodeNoTable is the code of errors for a statement without a table.
+	CodeNoTable ErrorCode = iota + 1
+	// CodeNoValues is the code of errors for an INSERT without values or
+	// select, or an UPDATE without SET clauses.
+	CodeNoValues
+	// CodeNoColumns is the code of errors for a SELECT without result columns.
+	CodeNoColumns
+	// CodeArgMismatch is the code of errors for a number of values or args
+	// that does not match the columns or placeholders they are bound to.
+	CodeArgMismatch
+	// CodeInvalidClause is the code of errors for clauses that cannot be
+	// combined, or are not supported by the dialect.
+	CodeInvalidClause
+	// CodeLimitExceeded is the code of errors for a query larger than the
+	// limits set with MaxJoins, MaxColumns or MaxConditions.
+	CodeLimitExceeded
+	// CodeInvalidArg is the code of errors for a struct or scan destination
+	// of an unsupported type, or whose fields do not match the columns.
+	CodeInvalidArg
+	// CodeNoReturning is the code of ErrNoReturning.
+	CodeNoReturning
+)
+
+// BuildError is returned by ToSql for a statement that cannot be built, and
+// by the struct helpers and ExecReturning for arguments they cannot use. Use
+// errors.Is with one of the Err values to check its Code:
+//
+//   if errors.Is(err, bsql.ErrNoTable) { ... }
+type BuildError struct {
+	Code ErrorCode
+	Msg  string
+}
+
+// Error returns the message of the error.
+func (e *BuildError) Error() string {
+	return e.Msg
+}
+
+// Is reports whether target is a BuildError with the same Code.
+func (e *BuildError) Is(target error) bool {
+	t, ok := target.(*BuildError)
+	return ok && t.Code == e.Code
+}
+
+// Sentinel errors to compare BuildErrors against with errors.Is.
+var (
+	ErrNoTable       = &BuildError{Code: CodeNoTable, Msg: "statement has no table"}
+	ErrNoValues      = &BuildError{Code: CodeNoValues, Msg: "statement has no values"}
+	ErrNoColumns     = &BuildError{Code: CodeNoColumns, Msg: "statement has no result columns"}
+	ErrArgMismatch   = &BuildError{Code: CodeArgMismatch, Msg: "number of values does not match"}
+	ErrInvalidClause = &BuildError{Code: CodeInvalidClause, Msg: "statement has invalid clauses"}
+	ErrLimitExceeded = &BuildError{Code: CodeLimitExceeded, Msg: "statement exceeds its limits"}
+	ErrInvalidArg    = &BuildError{Code: CodeInvalidArg, Msg: "invalid struct or scan destination"}
+)
+
+func buildErrorf(code ErrorCode, format string, args ...interface{}) error {
+	return &BuildError{Code: code, Msg: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,61 @@
+package bsql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildErrorCodes(t *testing.T) {
+	_, _, err := Insert("").Values(1).ToSql()
+	assert.True(t, errors.Is(err, ErrNoTable))
+	assert.False(t, errors.Is(err, ErrNoValues))
+	assert.EqualError(t, err, "insert statements must specify a table")
+
+	var buildErr *BuildError
+	assert.True(t, errors.As(err, &buildErr))
+	assert.Equal(t, CodeNoTable, buildErr.Code)
+
+	_, _, err = Insert("").ToSql()
+	assert.True(t, errors.Is(err, ErrNoTable))
+	assert.True(t, errors.Is(err, ErrNoValues))
+
+	_, _, err = Select().From("t").ToSql()
+	assert.True(t, errors.Is(err, ErrNoColumns))
+
+	_, _, err = Select("*").From("t").Where("a = ? AND b = ?", 1).StrictArgs(true).ToSql()
+	assert.True(t, errors.Is(err, ErrArgMismatch))
+
+	_, _, err = Delete("t").Using("u").Join("v ON v.id = t.id").ToSql()
+	assert.True(t, errors.Is(err, ErrInvalidClause))
+
+	_, _, err = Select("*").TableSample("BERNOULLI", 10).From("t").ToSql()
+	assert.True(t, errors.Is(err, ErrNoTable))
+
+	_, _, err = Select("a").From("t").OrderByPosition(2).ToSql()
+	assert.True(t, errors.Is(err, ErrInvalidClause))
+
+	_, _, err = Select("*").From("t").Where(Case()).ToSql()
+	assert.True(t, errors.Is(err, ErrInvalidClause))
+
+	_, _, err = Insert("t").Values(1).OnConflict("id").ToSql()
+	assert.True(t, errors.Is(err, ErrInvalidClause))
+
+	_, _, err = UpdateFromValues("t", "id", [][]interface{}{{"a"}}, []string{"name"}).ToSql()
+	assert.True(t, errors.Is(err, ErrInvalidClause))
+
+	_, _, err = Select("*").From("t").Join("u").Join("v").MaxJoins(1).ToSql()
+	assert.True(t, errors.Is(err, ErrLimitExceeded))
+
+	_, _, err = Insert("t").SetStruct(1).ToSql()
+	assert.True(t, errors.Is(err, ErrInvalidArg))
+
+	err = scanStructs(&RowsStub{}, []int{})
+	assert.True(t, errors.Is(err, ErrInvalidArg))
+
+	err = Insert("t").Values(1).RunWith(&DBStub{}).ExecReturning(context.Background())
+	assert.True(t, errors.As(err, &buildErr))
+	assert.Equal(t, CodeNoReturning, buildErr.Code)
+}
@@ -0,0 +1,269 @@
+package bsql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// Execer is the interface that wraps the Exec method.
+//
+// Exec executes the given query as implemented by database/sql.Exec.
+type Execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// Queryer is the interface that wraps the Query method.
+//
+// Query executes the given query as implemented by database/sql.Query.
+type Queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// QueryRower is the interface that wraps the QueryRow method.
+//
+// QueryRow executes the given query as implemented by database/sql.QueryRow.
+type QueryRower interface {
+	QueryRow(query string, args ...interface{}) RowScanner
+}
+
+// ExecerContext is the interface that wraps the ExecContext method.
+//
+// ExecContext executes the given query as implemented by database/sql.ExecContext.
+type ExecerContext interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// QueryerContext is the interface that wraps the QueryContext method.
+//
+// QueryContext executes the given query as implemented by database/sql.QueryContext.
+type QueryerContext interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// QueryRowerContext is the interface that wraps the QueryRowContext method.
+//
+// QueryRowContext executes the given query as implemented by database/sql.QueryRowContext.
+type QueryRowerContext interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) RowScanner
+}
+
+// RowScanner is the interface that wraps the Scan method.
+//
+// Scan behaves like database/sql.Row.Scan.
+type RowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// BaseRunner groups the Execer and Queryer interfaces.
+type BaseRunner interface {
+	Execer
+	Queryer
+}
+
+// Runner groups the Execer, Queryer and QueryRower interfaces.
+type Runner interface {
+	Execer
+	Queryer
+	QueryRower
+}
+
+// RunnerContext groups the Runner interface with its context-aware
+// counterparts.
+type RunnerContext interface {
+	Runner
+	ExecerContext
+	QueryerContext
+	QueryRowerContext
+}
+
+// StdSql encompasses the standard methods of *sql.DB and *sql.Tx that might
+// be used by bsql.
+type StdSql interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// StdSqlCtx encompasses StdSql and the context-aware methods shared by
+// *sql.DB, *sql.Tx and *sql.Conn.
+type StdSqlCtx interface {
+	StdSql
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type stdsqlRunner struct {
+	StdSql
+}
+
+func (r *stdsqlRunner) QueryRow(query string, args ...interface{}) RowScanner {
+	return r.StdSql.QueryRow(query, args...)
+}
+
+type stdsqlCtxRunner struct {
+	StdSqlCtx
+}
+
+func (r *stdsqlCtxRunner) QueryRow(query string, args ...interface{}) RowScanner {
+	return r.StdSqlCtx.QueryRow(query, args...)
+}
+
+func (r *stdsqlCtxRunner) QueryRowContext(ctx context.Context, query string, args ...interface{}) RowScanner {
+	return r.StdSqlCtx.QueryRowContext(ctx, query, args...)
+}
+
+// WrapStdSql wraps a type implementing the standard SQL interface (e.g.
+// *sql.DB) into a Runner.
+//
+// RunWith does this automatically, so it is only needed when using the
+// *With functions directly.
+func WrapStdSql(stdSql StdSql) Runner {
+	return &stdsqlRunner{stdSql}
+}
+
+// WrapStdSqlCtx wraps a type implementing the standard SQL interface plus
+// context methods (e.g. *sql.DB) into a RunnerContext.
+//
+// See WrapStdSql.
+func WrapStdSqlCtx(stdSqlCtx StdSqlCtx) RunnerContext {
+	return &stdsqlCtxRunner{stdSqlCtx}
+}
+
+func wrapRunner(runner BaseRunner) BaseRunner {
+	switch r := runner.(type) {
+	case StdSqlCtx:
+		return WrapStdSqlCtx(r)
+	case StdSql:
+		return WrapStdSql(r)
+	}
+	return runner
+}
+
+// ErrRunnerNotSet is returned when executing a builder without a runner.
+var ErrRunnerNotSet = errors.New("cannot run; no Runner set (RunWith)")
+
+// ErrRunnerNotQueryRunner is returned by QueryRow when the runner does not
+// implement QueryRower, or QueryRowerContext for QueryRowContext.
+var ErrRunnerNotQueryRunner = errors.New("cannot QueryRow; Runner is not a QueryRower")
+
+// ErrNoContextSupport is returned by the context-aware methods when the
+// runner does not implement the matching context interface.
+var ErrNoContextSupport = errors.New("cannot run with context; Runner does not support it")
+
+// ExecWith executes the SQL returned by s with db.
+func ExecWith(db Execer, s Sqlizer) (res sql.Result, err error) {
+	query, args, err := s.ToSql()
+	if err != nil {
+		return
+	}
+	return db.Exec(query, args...)
+}
+
+// QueryWith executes the SQL returned by s with db.
+func QueryWith(db Queryer, s Sqlizer) (rows *sql.Rows, err error) {
+	query, args, err := s.ToSql()
+	if err != nil {
+		return
+	}
+	return db.Query(query, args...)
+}
+
+// QueryRowWith executes the SQL returned by s with db.
+//
+// Errors building the query are reported by Scan of the returned RowScanner.
+func QueryRowWith(db QueryRower, s Sqlizer) RowScanner {
+	query, args, err := s.ToSql()
+	if err != nil {
+		return &Row{err: err}
+	}
+	return &Row{RowScanner: db.QueryRow(query, args...)}
+}
+
+// ExecContextWith executes the SQL returned by s with db.
+func ExecContextWith(ctx context.Context, db ExecerContext, s Sqlizer) (res sql.Result, err error) {
+	query, args, err := s.ToSql()
+	if err != nil {
+		return
+	}
+	return db.ExecContext(ctx, query, args...)
+}
+
+// QueryContextWith executes the SQL returned by s with db.
+func QueryContextWith(ctx context.Context, db QueryerContext, s Sqlizer) (rows *sql.Rows, err error) {
+	query, args, err := s.ToSql()
+	if err != nil {
+		return
+	}
+	return db.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContextWith executes the SQL returned by s with db.
+//
+// Errors building the query are reported by Scan of the returned RowScanner.
+func QueryRowContextWith(ctx context.Context, db QueryRowerContext, s Sqlizer) RowScanner {
+	query, args, err := s.ToSql()
+	if err != nil {
+		return &Row{err: err}
+	}
+	return &Row{RowScanner: db.QueryRowContext(ctx, query, args...)}
+}
+
+func exec(runner BaseRunner, s Sqlizer) (sql.Result, error) {
+	if runner == nil {
+		return nil, ErrRunnerNotSet
+	}
+	return ExecWith(runner, s)
+}
+
+func query(runner BaseRunner, s Sqlizer) (*sql.Rows, error) {
+	if runner == nil {
+		return nil, ErrRunnerNotSet
+	}
+	return QueryWith(runner, s)
+}
+
+func queryRow(runner BaseRunner, s Sqlizer) RowScanner {
+	if runner == nil {
+		return &Row{err: ErrRunnerNotSet}
+	}
+	queryRower, ok := runner.(QueryRower)
+	if !ok {
+		return &Row{err: ErrRunnerNotQueryRunner}
+	}
+	return QueryRowWith(queryRower, s)
+}
+
+func execContext(ctx context.Context, runner BaseRunner, s Sqlizer) (sql.Result, error) {
+	if runner == nil {
+		return nil, ErrRunnerNotSet
+	}
+	execer, ok := runner.(ExecerContext)
+	if !ok {
+		return nil, ErrNoContextSupport
+	}
+	return ExecContextWith(ctx, execer, s)
+}
+
+func queryContext(ctx context.Context, runner BaseRunner, s Sqlizer) (*sql.Rows, error) {
+	if runner == nil {
+		return nil, ErrRunnerNotSet
+	}
+	queryer, ok := runner.(QueryerContext)
+	if !ok {
+		return nil, ErrNoContextSupport
+	}
+	return QueryContextWith(ctx, queryer, s)
+}
+
+func queryRowContext(ctx context.Context, runner BaseRunner, s Sqlizer) RowScanner {
+	if runner == nil {
+		return &Row{err: ErrRunnerNotSet}
+	}
+	queryRower, ok := runner.(QueryRowerContext)
+	if !ok {
+		return &Row{err: ErrRunnerNotQueryRunner}
+	}
+	return QueryRowContextWith(ctx, queryRower, s)
+}
@@ -0,0 +1,254 @@
+package bsql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type ctxKey struct{}
+
+// DBStub records the queries it is asked to run. It models the methods of
+// both *sql.DB and *sql.Tx.
+type DBStub struct {
+	err error
+
+	// row holds the values returned by QueryRowContext.
+	row []interface{}
+
+	LastContext context.Context
+
+	LastExecSql  string
+	LastExecArgs []interface{}
+
+	LastQuerySql  string
+	LastQueryArgs []interface{}
+
+	LastQueryRowSql  string
+	LastQueryRowArgs []interface{}
+
+	PrepareCount   int
+	LastPrepareSql string
+}
+
+var StubError = errors.New("this is a stub; this is only a stub")
+
+func (s *DBStub) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return s.ExecContext(context.Background(), query, args...)
+}
+
+func (s *DBStub) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return s.QueryContext(context.Background(), query, args...)
+}
+
+func (s *DBStub) QueryRow(query string, args ...interface{}) RowScanner {
+	return s.QueryRowContext(context.Background(), query, args...)
+}
+
+func (s *DBStub) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	s.LastContext = ctx
+	s.LastExecSql = query
+	s.LastExecArgs = args
+	return nil, s.err
+}
+
+func (s *DBStub) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	s.LastContext = ctx
+	s.LastQuerySql = query
+	s.LastQueryArgs = args
+	return nil, s.err
+}
+
+func (s *DBStub) QueryRowContext(ctx context.Context, query string, args ...interface{}) RowScanner {
+	s.LastContext = ctx
+	s.LastQueryRowSql = query
+	s.LastQueryRowArgs = args
+	return &Row{RowScanner: &RowStub{values: s.row}, err: s.err}
+}
+
+// PrepareContext prepares query with the stub database/sql driver, see
+// stubDriver.
+func (s *DBStub) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	s.LastContext = ctx
+	s.PrepareCount++
+	s.LastPrepareSql = query
+	if s.err != nil {
+		return nil, s.err
+	}
+	return stubSqlDB.PrepareContext(ctx, query)
+}
+
+// RowStub is a RowScanner returning canned values.
+type RowStub struct {
+	Scanned bool
+	values  []interface{}
+}
+
+func (r *RowStub) Scan(dest ...interface{}) error {
+	r.Scanned = true
+	if len(dest) > len(r.values) {
+		return errors.New("not enough values in row")
+	}
+	for i, d := range dest {
+		reflect.ValueOf(d).Elem().Set(reflect.ValueOf(r.values[i]))
+	}
+	return nil
+}
+
+// RowsStub is a rowsScanner returning canned rows.
+type RowsStub struct {
+	columns []string
+	rows    [][]interface{}
+	next    int
+}
+
+func (r *RowsStub) Columns() ([]string, error) {
+	return r.columns, nil
+}
+
+func (r *RowsStub) Next() bool {
+	r.next++
+	return r.next <= len(r.rows)
+}
+
+func (r *RowsStub) Scan(dest ...interface{}) error {
+	return (&RowStub{values: r.rows[r.next-1]}).Scan(dest...)
+}
+
+func (r *RowsStub) Err() error {
+	return nil
+}
+
+func TestExecContextWith(t *testing.T) {
+	db := &DBStub{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, 1)
+	_, err := ExecContextWith(ctx, db, Expr("DELETE FROM t WHERE a = ?", 1))
+	assert.NoError(t, err)
+	assert.Equal(t, ctx, db.LastContext)
+	assert.Equal(t, "DELETE FROM t WHERE a = ?", db.LastExecSql)
+	assert.Equal(t, []interface{}{1}, db.LastExecArgs)
+}
+
+func TestQueryRowContextWithBuildError(t *testing.T) {
+	db := &DBStub{}
+	err := QueryRowContextWith(context.Background(), db, Select()).Scan()
+	assert.Error(t, err)
+	assert.Empty(t, db.LastQueryRowSql)
+}
+
+func TestRunContextNoRunner(t *testing.T) {
+	ctx := context.Background()
+	b := Select("a").From("t")
+
+	_, err := b.ExecContext(ctx)
+	assert.Equal(t, ErrRunnerNotSet, err)
+
+	_, err = b.QueryContext(ctx)
+	assert.Equal(t, ErrRunnerNotSet, err)
+
+	err = b.QueryRowContext(ctx).Scan()
+	assert.Equal(t, ErrRunnerNotSet, err)
+}
+
+// baseRunnerStub implements BaseRunner only.
+type baseRunnerStub struct {
+	db DBStub
+}
+
+func (s *baseRunnerStub) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return s.db.Exec(query, args...)
+}
+
+func (s *baseRunnerStub) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return s.db.Query(query, args...)
+}
+
+func TestRunNoQueryRower(t *testing.T) {
+	b := Select("a").From("t").RunWith(&baseRunnerStub{})
+
+	err := b.QueryRow().Scan()
+	assert.Equal(t, ErrRunnerNotQueryRunner, err)
+
+	err = b.QueryRowContext(context.Background()).Scan()
+	assert.Equal(t, ErrRunnerNotQueryRunner, err)
+}
+
+func TestRunNoContextSupport(t *testing.T) {
+	runner := &baseRunnerStub{}
+	b := Delete("t").Where(Eq{"a": 1}).RunWith(runner)
+
+	_, err := b.ExecContext(context.Background())
+	assert.Equal(t, ErrNoContextSupport, err)
+
+	_, err = b.QueryContext(context.Background())
+	assert.Equal(t, ErrNoContextSupport, err)
+
+	_, err = b.Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM t WHERE a = ?", runner.db.LastExecSql)
+}
+
+func TestRunNoRunner(t *testing.T) {
+	b := Update("t").Set("a", 1)
+
+	_, err := b.Exec()
+	assert.Equal(t, ErrRunnerNotSet, err)
+
+	_, err = b.Query()
+	assert.Equal(t, ErrRunnerNotSet, err)
+
+	err = b.QueryRow().Scan()
+	assert.Equal(t, ErrRunnerNotSet, err)
+}
+
+func TestStatementBuilderRunWith(t *testing.T) {
+	db := &DBStub{}
+	sb := StatementBuilder.RunWith(db).PlaceholderFormat(Dollar)
+
+	_, err := sb.Delete("t").Where(Eq{"a": 1}).ExecContext(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM t WHERE a = $1", db.LastExecSql)
+}
+
+func TestRunWithDB(t *testing.T) {
+	db := &sql.DB{}
+	assert.NotPanics(t, func() {
+		Select().RunWith(db)
+		Insert("t").RunWith(db)
+		Update("t").RunWith(db)
+		Delete("t").RunWith(db)
+	})
+
+	_, ok := Select().RunWith(db).runner.(RunnerContext)
+	assert.True(t, ok)
+}
+
+func TestRunWithTx(t *testing.T) {
+	tx := &sql.Tx{}
+	assert.NotPanics(t, func() {
+		Select().RunWith(tx)
+		Insert("t").RunWith(tx)
+		Update("t").RunWith(tx)
+		Delete("t").RunWith(tx)
+	})
+
+	_, ok := Select().RunWith(tx).runner.(RunnerContext)
+	assert.True(t, ok)
+}
+
+// stdSqlStub implements StdSql without the context methods.
+type stdSqlStub struct {
+	StdSql
+}
+
+func TestRunWithStdSql(t *testing.T) {
+	runner := Select().RunWith(stdSqlStub{}).runner
+	_, ok := runner.(Runner)
+	assert.True(t, ok)
+	_, ok = runner.(RunnerContext)
+	assert.False(t, ok)
+}
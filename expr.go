@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -22,28 +24,75 @@ func Expr(sql string, args ...interface{}) expr {
 	return expr{sql: sql, args: args}
 }
 
+// Raw is literal SQL with no args. As a value in Eq, Set, SetMap or Values it
+// is written in place of a placeholder.
+// Ex:
+//     .Set("updated_at", Raw("NOW()")) == "updated_at = NOW()"
+//     .Where(Eq{"expires_at": Raw("CURRENT_DATE")}) == "expires_at = CURRENT_DATE"
+func Raw(sql string) expr {
+	return expr{sql: sql}
+}
+
+// ToSql builds the expression into a SQL string and bound args.
+//
+// Sqlizer args are inlined in place of their placeholder. A slice arg (other
+// than []byte) written as "IN (?)" is expanded to one placeholder per
+// element, so Expr("id IN (?)", []int{1, 2, 3}) renders "id IN (?,?,?)";
+// anywhere else, as in "id = ANY(?)", it is bound as a single arg. An empty
+// slice after a plain column renders like Eq: "(1=0)" for IN and "(1=1)" for
+// NOT IN.
 func (e expr) ToSql() (string, []interface{}, error) {
-	if !hasSqlizer(e.args) {
+	if !needsRewrite(e.args) {
 		return e.sql, e.args, nil
 	}
 
 	args := make([]interface{}, 0, len(e.args))
-	sql, err := replacePlaceholders(e.sql, func(buf *bytes.Buffer, i int) error {
+	sql, err := rewritePlaceholdersRest(e.sql, true, func(buf *bytes.Buffer, i int, rest string) error {
 		if i > len(e.args) {
 			buf.WriteRune('?')
 			return nil
 		}
 		switch arg := e.args[i-1].(type) {
 		case Sqlizer:
-			sql, vs, err := arg.ToSql()
+			sql, vs, err := nestedToSql(arg)
 			if err != nil {
 				return err
 			}
 			args = append(args, vs...)
-			fmt.Fprintf(buf, sql)
+			buf.WriteString(sql)
 		default:
-			args = append(args, arg)
-			buf.WriteRune('?')
+			if !isListType(arg) || !strings.HasPrefix(rest, ")") {
+				args = append(args, arg)
+				buf.WriteRune('?')
+				return nil
+			}
+			in := inListRegexp.FindSubmatchIndex(buf.Bytes())
+			if in == nil {
+				args = append(args, arg)
+				buf.WriteRune('?')
+				return nil
+			}
+
+			argVal := reflect.ValueOf(arg)
+			if argVal.Len() > 0 {
+				for i := 0; i < argVal.Len(); i++ {
+					args = append(args, argVal.Index(i).Interface())
+				}
+				buf.WriteString(Placeholders(argVal.Len()))
+				return nil
+			}
+			if in[2] == -1 || strings.EqualFold(strings.TrimSpace(string(buf.Bytes()[in[2]:in[3]])), "NOT") {
+				buf.WriteString("NULL")
+				return nil
+			}
+			// Replace "col [NOT] IN (" with "(1=0" or "(1=1"; the ")" that
+			// follows the placeholder closes it.
+			empty := "(1=0"
+			if in[4] != -1 {
+				empty = "(1=1"
+			}
+			buf.Truncate(in[2])
+			buf.WriteString(empty)
 		}
 		return nil
 	})
@@ -53,6 +102,128 @@ func (e expr) ToSql() (string, []interface{}, error) {
 	return sql, args, nil
 }
 
+// inListRegexp matches the "IN (" before a placeholder whose slice arg is
+// expanded, capturing the column and NOT when the column is a plain
+// identifier.
+var inListRegexp = regexp.MustCompile(`(?i)(?:(?:^|[\s(])([A-Za-z_][A-Za-z0-9_$.]*\s+)(NOT\s+)?)?\bIN\s*\($`)
+
+// Cast renders inner followed by a PostgreSQL cast to sqlType. A Sqlizer is
+// parenthesized with its args spliced in, a string is used as SQL, and any
+// other value is bound as an arg:
+//     Cast(42, "text") == "?::text"
+//     Cast("created_at", "date") == "created_at::date"
+//     Cast(Select("data").From("docs"), "jsonb") == "(SELECT data FROM docs)::jsonb"
+//
+// As with Expr, a Cast value in Eq is compared directly, e.g.
+// Eq{"id": Cast(Expr("?", id), "uuid")} renders "id = (?)::uuid".
+func Cast(inner interface{}, sqlType string) expr {
+	switch v := inner.(type) {
+	case Sqlizer:
+		return Expr("(?)::"+sqlType, v)
+	case string:
+		return Expr(v + "::" + sqlType)
+	default:
+		return Expr("?::"+sqlType, v)
+	}
+}
+
+type namedExpr struct {
+	sql   string
+	named map[string]interface{}
+}
+
+// NamedExpr builds an expression from sql with ":name" parameters, bound from
+// named. Each occurrence is rendered as a ? placeholder and binds its value
+// again, so a parameter can be referenced several times. Values are handled
+// as Expr args, so a slice in "IN (:name)" is expanded and Sqlizers are
+// inlined. "::" (as in a PostgreSQL cast) is not a parameter.
+// Ex:
+//     .Where(NamedExpr("owner_id = :id OR assignee_id = :id", map[string]interface{}{"id": 7}))
+//     == "owner_id = ? OR assignee_id = ?" with args [7 7]
+func NamedExpr(sql string, named map[string]interface{}) Sqlizer {
+	return namedExpr{sql: sql, named: named}
+}
+
+// ToSql builds the expression into a SQL string and bound args.
+func (e namedExpr) ToSql() (string, []interface{}, error) {
+	buf := &bytes.Buffer{}
+	var args []interface{}
+	sql := e.sql
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		if c != ':' {
+			buf.WriteByte(c)
+			continue
+		}
+		if i+1 < len(sql) && sql[i+1] == ':' {
+			buf.WriteString("::")
+			i++
+			continue
+		}
+		end := i + 1
+		for end < len(sql) && isNameByte(sql[end], end == i+1) {
+			end++
+		}
+		if end == i+1 {
+			buf.WriteByte(c)
+			continue
+		}
+		name := sql[i+1 : end]
+		val, ok := e.named[name]
+		if !ok {
+			return "", nil, fmt.Errorf("missing value for named arg :%s", name)
+		}
+		buf.WriteByte('?')
+		args = append(args, val)
+		i = end - 1
+	}
+	return Expr(buf.String(), args...).ToSql()
+}
+
+// isNameByte reports whether c can appear in a named arg, first being true
+// for its first byte.
+func isNameByte(c byte, first bool) bool {
+	switch {
+	case c == '_', 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		return true
+	case '0' <= c && c <= '9':
+		return !first
+	}
+	return false
+}
+
+type concatExpr []interface{}
+
+// ConcatExpr concatenates strings and Sqlizers into one expression. Strings
+// are emitted verbatim and Sqlizers contribute their SQL and args, in order.
+// Ex:
+//     ConcatExpr("SELECT * FROM t WHERE ", Eq{"id": 1}, " LIMIT 1")
+func ConcatExpr(parts ...interface{}) concatExpr {
+	return concatExpr(parts)
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (ce concatExpr) ToSql() (sql string, args []interface{}, err error) {
+	buf := &bytes.Buffer{}
+	for _, part := range ce {
+		switch p := part.(type) {
+		case string:
+			buf.WriteString(p)
+		case Sqlizer:
+			partSql, partArgs, err := nestedToSql(p)
+			if err != nil {
+				return "", nil, err
+			}
+			buf.WriteString(partSql)
+			args = append(args, partArgs...)
+		default:
+			return "", nil, fmt.Errorf("concat parts must be string or Sqlizer, not %T", part)
+		}
+	}
+	sql = buf.String()
+	return
+}
+
 type exprs []expr
 
 func (es exprs) AppendToSql(w io.Writer, sep string, args []interface{}) ([]interface{}, error) {
@@ -63,15 +234,34 @@ func (es exprs) AppendToSql(w io.Writer, sep string, args []interface{}) ([]inte
 				return nil, err
 			}
 		}
-		_, err := io.WriteString(w, e.sql)
+		sql, eArgs, err := e.ToSql()
 		if err != nil {
 			return nil, err
 		}
-		args = append(args, e.args...)
+		_, err = io.WriteString(w, sql)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, eArgs...)
 	}
 	return args, nil
 }
 
+type empty struct{}
+
+// Empty returns a Sqlizer that renders nothing. Builders skip empty result
+// columns, joins and WHERE and HAVING parts without leaving stray keywords
+// or separators, so Empty can stand in for those when no content is needed.
+// An empty value of Values or Set is an error, as is a query whose result
+// columns are all empty.
+func Empty() Sqlizer {
+	return empty{}
+}
+
+func (empty) ToSql() (string, []interface{}, error) {
+	return "", nil, nil
+}
+
 // aliasExpr helps to alias part of SQL query generated with underlying "expr"
 type aliasExpr struct {
 	expr  Sqlizer
@@ -82,12 +272,16 @@ type aliasExpr struct {
 // defined as complex expression like IF or CASE
 // Ex:
 //		.Column(Alias(caseStmt, "case_column"))
+//
+// It also selects a scalar subquery as a column, with the subquery args
+// bound in select-list order:
+//		.Column(Alias(Select("COUNT(*)").From("orders o").Where("o.uid = u.id"), "order_count"))
 func Alias(expr Sqlizer, alias string) aliasExpr {
 	return aliasExpr{expr, alias}
 }
 
 func (e aliasExpr) ToSql() (sql string, args []interface{}, err error) {
-	sql, args, err = e.expr.ToSql()
+	sql, args, err = nestedToSql(e.expr)
 	if err == nil {
 		sql = fmt.Sprintf("(%s) AS %s", sql, e.alias)
 	}
@@ -97,13 +291,18 @@ func (e aliasExpr) ToSql() (sql string, args []interface{}, err error) {
 // Eq is syntactic sugar for use with Where/Having/Set methods.
 // Ex:
 //     .Where(Eq{"id": 1})
+//
+// A slice value renders "id IN (?,?,?)", and a Sqlizer value such as a
+// SelectBuilder renders "id IN (subquery)" with the subquery args spliced in.
+// Values built with Expr are compared directly, e.g. "id = u.id".
 type Eq map[string]interface{}
 
-func (eq Eq) toSql(useNotOpr bool) (sql string, args []interface{}, err error) {
+func (eq Eq) toSql(useNotOpr, anyArray bool) (sql string, args []interface{}, err error) {
 	var (
 		exprs       []string
 		equalOpr    = "="
 		inOpr       = "IN"
+		quantifier  = "ANY"
 		nullOpr     = "IS"
 		inEmptyExpr = "(1=0)" // Portable FALSE
 	)
@@ -111,11 +310,30 @@ func (eq Eq) toSql(useNotOpr bool) (sql string, args []interface{}, err error) {
 	if useNotOpr {
 		equalOpr = "<>"
 		inOpr = "NOT IN"
+		quantifier = "ALL"
 		nullOpr = "IS NOT"
 		inEmptyExpr = "(1=1)" // Portable TRUE
 	}
 
-	for key, val := range eq {
+	for _, key := range sortedKeys(eq) {
+		val := eq[key]
+
+		if sqlizer, ok := val.(Sqlizer); ok {
+			var valSql string
+			var valArgs []interface{}
+			if valSql, valArgs, err = nestedToSql(sqlizer); err != nil {
+				return
+			}
+			switch val.(type) {
+			case expr, argRef:
+				exprs = append(exprs, fmt.Sprintf("%s %s %s", key, equalOpr, valSql))
+			default:
+				exprs = append(exprs, fmt.Sprintf("%s %s (%s)", key, inOpr, valSql))
+			}
+			args = append(args, valArgs...)
+			continue
+		}
+
 		expr := ""
 
 		switch v := val.(type) {
@@ -135,6 +353,9 @@ func (eq Eq) toSql(useNotOpr bool) (sql string, args []interface{}, err error) {
 					if args == nil {
 						args = []interface{}{}
 					}
+				} else if anyArray {
+					args = append(args, val)
+					expr = fmt.Sprintf("%s %s %s(?)", key, equalOpr, quantifier)
 				} else {
 					for i := 0; i < valVal.Len(); i++ {
 						args = append(args, valVal.Index(i).Interface())
@@ -154,7 +375,7 @@ func (eq Eq) toSql(useNotOpr bool) (sql string, args []interface{}, err error) {
 
 // ToSql builds the query into a SQL string and bound args.
 func (eq Eq) ToSql() (sql string, args []interface{}, err error) {
-	return eq.toSql(false)
+	return eq.toSql(false, false)
 }
 
 // NotEq is syntactic sugar for use with Where/Having/Set methods.
@@ -164,7 +385,7 @@ type NotEq Eq
 
 // ToSql builds the query into a SQL string and bound args.
 func (neq NotEq) ToSql() (sql string, args []interface{}, err error) {
-	return Eq(neq).toSql(true)
+	return Eq(neq).toSql(true, false)
 }
 
 // Lt is syntactic sugar for use with Where/Having/Set methods.
@@ -186,7 +407,8 @@ func (lt Lt) toSql(opposite, orEq bool) (sql string, args []interface{}, err err
 		opr = fmt.Sprintf("%s%s", opr, "=")
 	}
 
-	for key, val := range lt {
+	for _, key := range sortedKeys(lt) {
+		val := lt[key]
 		expr := ""
 
 		switch v := val.(type) {
@@ -245,12 +467,155 @@ func (gtOrEq GtOrEq) ToSql() (sql string, args []interface{}, err error) {
 	return Lt(gtOrEq).toSql(true, true)
 }
 
+// Like is syntactic sugar for use with LIKE conditions.
+// Ex:
+//     .Where(Like{"name": "%irrel"})
+type Like map[string]interface{}
+
+func (lk Like) toSql(opr string) (sql string, args []interface{}, err error) {
+	var exprs []string
+	for _, key := range sortedKeys(lk) {
+		val := lk[key]
+
+		switch v := val.(type) {
+		case driver.Valuer:
+			if val, err = v.Value(); err != nil {
+				return
+			}
+		}
+
+		if val == nil {
+			err = fmt.Errorf("cannot use null with like operators")
+			return
+		}
+		if isListType(val) {
+			err = fmt.Errorf("cannot use array or slice with like operators")
+			return
+		}
+
+		exprs = append(exprs, fmt.Sprintf("%s %s ?", key, opr))
+		args = append(args, val)
+	}
+	sql = strings.Join(exprs, " AND ")
+	return
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (lk Like) ToSql() (sql string, args []interface{}, err error) {
+	return lk.toSql("LIKE")
+}
+
+// ILike is syntactic sugar for use with ILIKE conditions.
+// Ex:
+//     .Where(ILike{"name": "sq%"})
+//
+// ILIKE is PostgreSQL specific extension
+type ILike Like
+
+// ToSql builds the query into a SQL string and bound args.
+func (ilk ILike) ToSql() (sql string, args []interface{}, err error) {
+	return Like(ilk).toSql("ILIKE")
+}
+
+// compare renders a comparison of an arbitrary expression against a value.
+type compare struct {
+	lhs   Sqlizer
+	opr   string
+	value interface{}
+}
+
+func (c compare) ToSql() (sql string, args []interface{}, err error) {
+	lhsSql, lhsArgs, err := nestedToSql(c.lhs)
+	if err != nil {
+		return
+	}
+	args = append(args, lhsArgs...)
+
+	valSql := "?"
+	if s, ok := c.value.(Sqlizer); ok {
+		var valArgs []interface{}
+		if valSql, valArgs, err = nestedToSql(s); err != nil {
+			return
+		}
+		args = append(args, valArgs...)
+	} else {
+		args = append(args, c.value)
+	}
+
+	sql = fmt.Sprintf("%s %s %s", lhsSql, c.opr, valSql)
+	return
+}
+
+type between struct {
+	column string
+	lo, hi interface{}
+	not    bool
+}
+
+// Between is syntactic sugar for range conditions.
+// Ex:
+//     .Where(Between("age", 18, 65)) == "age BETWEEN 18 AND 65"
+func Between(column string, lo, hi interface{}) between {
+	return between{column: column, lo: lo, hi: hi}
+}
+
+// NotBetween is the negated form of Between.
+// Ex:
+//     .Where(NotBetween("age", 18, 65)) == "age NOT BETWEEN 18 AND 65"
+func NotBetween(column string, lo, hi interface{}) between {
+	return between{column: column, lo: lo, hi: hi, not: true}
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (bt between) ToSql() (sql string, args []interface{}, err error) {
+	opr := "BETWEEN"
+	if bt.not {
+		opr = "NOT BETWEEN"
+	}
+	sql = fmt.Sprintf("%s %s ? AND ?", bt.column, opr)
+	args = []interface{}{bt.lo, bt.hi}
+	return
+}
+
+type exists struct {
+	sub *SelectBuilder
+	not bool
+}
+
+// Exists is syntactic sugar for existence checks against a subquery.
+// Ex:
+//     .Where(Exists(Select("1").From("orders").Where("orders.user_id = users.id")))
+//     == "EXISTS (SELECT 1 FROM orders WHERE orders.user_id = users.id)"
+func Exists(sub *SelectBuilder) exists {
+	return exists{sub: sub}
+}
+
+// NotExists is the negated form of Exists.
+func NotExists(sub *SelectBuilder) exists {
+	return exists{sub: sub, not: true}
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (e exists) ToSql() (sql string, args []interface{}, err error) {
+	subSql, args, err := nestedToSql(e.sub)
+	if err != nil {
+		return
+	}
+
+	opr := "EXISTS"
+	if e.not {
+		opr = "NOT EXISTS"
+	}
+	sql = fmt.Sprintf("%s (%s)", opr, subSql)
+	return
+}
+
 type conj []Sqlizer
 
 func (c conj) join(sep string) (sql string, args []interface{}, err error) {
 	var sqlParts []string
 	for _, sqlizer := range c {
-		partSql, partArgs, err := sqlizer.ToSql()
+		partSql, partArgs, err := nestedToSql(sqlizer)
 		if err != nil {
 			return "", nil, err
 		}
@@ -285,18 +650,181 @@ func (o Or) ToSql() (string, []interface{}, error) {
 	return conj(o).join(" OR ")
 }
 
+// AnyOf renders each map as an AND of Eq conditions and joins them with OR,
+// e.g. for filters sent as a list of alternatives. Groups of more than one
+// condition are parenthesized; keys are sorted within each group. Empty maps
+// are skipped, and AnyOf with no conditions renders nothing.
+// Ex:
+//     .Where(AnyOf(map[string]interface{}{"a": 1, "b": 2}, map[string]interface{}{"a": 3}))
+//     == "((a = ? AND b = ?) OR a = ?)"
+func AnyOf(maps ...map[string]interface{}) Sqlizer {
+	or := NewOr()
+	for _, m := range maps {
+		and := NewAnd()
+		for _, key := range sortedKeys(m) {
+			and.Add(Eq{key: m[key]})
+		}
+		or.Add(and)
+	}
+	return or
+}
+
+// ConjBuilder assembles an AND or OR predicate incrementally, e.g. from
+// filters known only at runtime. Nil predicates are skipped; a builder with no
+// parts renders nothing and one with a single part renders that part without
+// parentheses.
+// Ex:
+//     NewAnd().
+//         Add(Eq{"active": true}).
+//         AddIf(name != "", Like{"name": name}).
+//         Add(NewOr().Add(Eq{"a": 1}).Add(Eq{"b": 2}))
+type ConjBuilder struct {
+	sep   string
+	parts []Sqlizer
+}
+
+// NewAnd returns a ConjBuilder that glues its parts with AND.
+func NewAnd() *ConjBuilder {
+	return &ConjBuilder{sep: " AND "}
+}
+
+// NewOr returns a ConjBuilder that glues its parts with OR.
+func NewOr() *ConjBuilder {
+	return &ConjBuilder{sep: " OR "}
+}
+
+// Add appends preds to the builder, skipping nils.
+func (b *ConjBuilder) Add(preds ...Sqlizer) *ConjBuilder {
+	for _, pred := range preds {
+		if pred != nil {
+			b.parts = append(b.parts, pred)
+		}
+	}
+	return b
+}
+
+// AddIf appends pred when cond is true and is a no-op otherwise.
+func (b *ConjBuilder) AddIf(cond bool, pred Sqlizer) *ConjBuilder {
+	if !cond {
+		return b
+	}
+	return b.Add(pred)
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (b *ConjBuilder) ToSql() (sql string, args []interface{}, err error) {
+	var sqlParts []string
+	for _, part := range b.parts {
+		partSql, partArgs, err := nestedToSql(part)
+		if err != nil {
+			return "", nil, err
+		}
+		if partSql != "" {
+			sqlParts = append(sqlParts, partSql)
+			args = append(args, partArgs...)
+		}
+	}
+	switch len(sqlParts) {
+	case 0:
+	case 1:
+		sql = sqlParts[0]
+	default:
+		sql = "(" + strings.Join(sqlParts, b.sep) + ")"
+	}
+	return
+}
+
+type not struct {
+	pred Sqlizer
+}
+
+// Not negates a predicate. It renders "NOT (pred)" with the args of pred;
+// predicates that are already parenthesized, like And and Or, are not wrapped
+// again.
+// Ex:
+//     .Where(Not(Or{Eq{"a": 1}, Eq{"b": 2}}))
+//     == "NOT (a = ? OR b = ?)"
+func Not(pred Sqlizer) Sqlizer {
+	return not{pred: pred}
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (n not) ToSql() (sql string, args []interface{}, err error) {
+	sql, args, err = nestedToSql(n.pred)
+	if err != nil || sql == "" {
+		return
+	}
+	if !isParenthesized(sql) {
+		sql = "(" + sql + ")"
+	}
+	sql = "NOT " + sql
+	return
+}
+
+// isParenthesized reports whether sql is enclosed in one pair of parentheses,
+// as opposed to e.g. "(a) OR (b)".
+func isParenthesized(sql string) bool {
+	if !strings.HasPrefix(sql, "(") || !strings.HasSuffix(sql, ")") {
+		return false
+	}
+	depth := 0
+	for i, r := range sql {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i == len(sql)-1
+			}
+		}
+	}
+	return false
+}
+
 func isListType(val interface{}) bool {
 	if driver.IsValue(val) {
 		return false
 	}
+	if _, ok := val.(driver.Valuer); ok {
+		return false
+	}
 	valVal := reflect.ValueOf(val)
 	return valVal.Kind() == reflect.Array || valVal.Kind() == reflect.Slice
 }
 
-func hasSqlizer(args []interface{}) bool {
+// sortedKeys returns the keys of m in sorted order, so that map based
+// predicates render deterministically.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// withoutNils returns a copy of m without the entries whose value is nil or a
+// nil pointer.
+func withoutNils(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for key, val := range m {
+		if val == nil {
+			continue
+		}
+		if rv := reflect.ValueOf(val); rv.Kind() == reflect.Ptr && rv.IsNil() {
+			continue
+		}
+		out[key] = val
+	}
+	return out
+}
+
+// needsRewrite reports whether args contain Sqlizers or slices that have to
+// be spliced into the SQL of an expression.
+func needsRewrite(args []interface{}) bool {
 	for _, arg := range args {
-		_, ok := arg.(Sqlizer)
-		if ok {
+		if _, ok := arg.(Sqlizer); ok || isListType(arg) {
 			return true
 		}
 	}
@@ -91,6 +91,23 @@ func TestEqBytesToSql(t *testing.T) {
 	assert.Equal(t, expectedArgs, args)
 }
 
+func TestEqSliceInWhere(t *testing.T) {
+	sql, args, err := Select("id").From("orders").
+		Where(Eq{"status": []string{"new", "paid"}}).
+		Where(Eq{"shop_id": []int64{7, 8, 9}}).
+		Where(Eq{"tag": []string{}}).
+		Where(Eq{"hash": []byte{0x01, 0x02}}).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT id FROM orders WHERE status IN ($1,$2) AND shop_id IN ($3,$4,$5) AND (1=0) AND hash = $6"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{"new", "paid", int64(7), int64(8), int64(9), []byte{0x01, 0x02}}
+	assert.Equal(t, expectedArgs, args)
+}
+
 func TestLtToSql(t *testing.T) {
 	b := Lt{"id": 1}
 	sql, args, err := b.ToSql()
@@ -212,3 +229,345 @@ func TestExprSqlizer(t *testing.T) {
 		assert.Equal(t, []interface{}{42, 42}, args)
 	}
 }
+
+func TestExprScalarAndSubquery(t *testing.T) {
+	sub := Select("id").From("banned").Where(Eq{"reason": "spam"})
+	b := Expr("x = ? AND y IN (?)", 1, sub)
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "x = ? AND y IN (SELECT id FROM banned WHERE reason = ?)", sql)
+	assert.Equal(t, []interface{}{1, "spam"}, args)
+
+	sql, args, err = Select("*").From("users").Where("team = ?", 7).
+		Suffix("AND id NOT IN (?) AND age > ?", sub, 18).PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE team = $1 AND id NOT IN (SELECT id FROM banned WHERE reason = $2) AND age > $3", sql)
+	assert.Equal(t, []interface{}{7, "spam", 18}, args)
+}
+
+func TestExprSqlOut(t *testing.T) {
+	var total int
+	out := sql.Out{Dest: &total}
+	b := Expr("CALL proc(?, ?)", 1, out)
+	sql, args, err := b.ToSql()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "CALL proc(?, ?)", sql)
+	assert.Equal(t, []interface{}{1, out}, args)
+}
+
+func TestEqMultiKeyToSql(t *testing.T) {
+	b := Eq{"c": 3, "a": 1, "b": nil}
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "a = ? AND b IS NULL AND c = ?"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{1, 3}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestNotEqMultiKeyToSql(t *testing.T) {
+	b := NotEq{"b": nil, "a": 1}
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "a <> ? AND b IS NOT NULL"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{1}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestGtOrEqMultiKeyToSql(t *testing.T) {
+	b := GtOrEq{"z": 2, "y": 1}
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "y >= ? AND z >= ?"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{1, 2}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestLtNilToSql(t *testing.T) {
+	_, _, err := Lt{"id": nil}.ToSql()
+	assert.Error(t, err)
+}
+
+func TestLikeToSql(t *testing.T) {
+	b := Like{"name": "%foo%"}
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "name LIKE ?"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{"%foo%"}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestILikeToSql(t *testing.T) {
+	b := ILike{"name": "sq%", "email": "%@example.com"}
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "email ILIKE ? AND name ILIKE ?"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{"%@example.com", "sq%"}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestEqSubqueryToSql(t *testing.T) {
+	sub := Select("id").From("banned").Where(Eq{"reason": "spam"})
+	b := Eq{"user_id": sub}
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "user_id IN (SELECT id FROM banned WHERE reason = ?)"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{"spam"}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestNotEqSubqueryToSql(t *testing.T) {
+	sub := Select("id").From("banned")
+	b := NotEq{"user_id": sub}
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "user_id NOT IN (SELECT id FROM banned)"
+	assert.Equal(t, expectedSql, sql)
+	assert.Empty(t, args)
+}
+
+func TestBetweenToSql(t *testing.T) {
+	b := Or{Between("age", 18, 65), NotBetween("score", 1, 10)}
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "(age BETWEEN ? AND ? OR score NOT BETWEEN ? AND ?)"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{18, 65, 1, 10}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestEmptyInConj(t *testing.T) {
+	sql, args, err := And{Empty(), Eq{"a": 1}, Empty(), Eq{"b": 2}}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "(a = ? AND b = ?)", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+
+	sql, args, err = Or{Empty(), Empty()}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "", sql)
+	assert.Empty(t, args)
+}
+
+func TestConjBuilder(t *testing.T) {
+	sql, args, err := NewAnd().Add(nil).AddIf(false, Eq{"a": 1}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "", sql)
+	assert.Empty(t, args)
+
+	sql, args, err = NewAnd().Add(nil, Eq{"a": 1}).AddIf(false, Eq{"b": 2}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "a = ?", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, args, err = NewAnd().
+		Add(Eq{"a": 1}).
+		AddIf(true, NewOr().Add(Eq{"b": 2}).Add(Eq{"c": 3})).
+		Add(NewOr()).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "(a = ? AND (b = ? OR c = ?))", sql)
+	assert.Equal(t, []interface{}{1, 2, 3}, args)
+
+	sql, _, err = Select("*").From("t").Where(NewOr()).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t", sql)
+}
+
+func TestExprSliceToSql(t *testing.T) {
+	sql, args, err := Expr("id IN (?) AND kind = ?", []int{1, 2, 3}, "a").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "id IN (?,?,?) AND kind = ?", sql)
+	assert.Equal(t, []interface{}{1, 2, 3, "a"}, args)
+
+	sql, args, err = Expr("id IN (?)", []int{}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "(1=0)", sql)
+	assert.Empty(t, args)
+
+	sql, args, err = Expr("kind = ? AND u.id NOT IN (?)", "a", []int{}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "kind = ? AND (1=1)", sql)
+	assert.Equal(t, []interface{}{"a"}, args)
+
+	sql, args, err = Expr("id = ANY(?)", []int{1, 2}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "id = ANY(?)", sql)
+	assert.Equal(t, []interface{}{[]int{1, 2}}, args)
+
+	sql, args, err = Expr("tags @> ?", []string{"a"}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "tags @> ?", sql)
+	assert.Equal(t, []interface{}{[]string{"a"}}, args)
+
+	sql, args, err = Expr("data = ?", []byte("abc")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "data = ?", sql)
+	assert.Equal(t, []interface{}{[]byte("abc")}, args)
+}
+
+func TestExprSliceKeepsEscapes(t *testing.T) {
+	b := Select("id").From("t").
+		Where("data ?? 'key' AND id IN (?)", []int{1, 2}).
+		PlaceholderFormat(Dollar)
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t WHERE data ? 'key' AND id IN ($1,$2)", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+}
+
+func TestExprSqlizerWithPercent(t *testing.T) {
+	sql, args, err := Expr("a = ?", Expr("b LIKE '%s'")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "a = b LIKE '%s'", sql)
+	assert.Empty(t, args)
+}
+
+func TestExistsToSql(t *testing.T) {
+	sub := Select("1").From("orders").Where("orders.user_id = users.id AND orders.total > ?", 100)
+	b := Select("id").From("users").
+		Where(Eq{"active": true}).
+		Where(Exists(sub)).
+		Where(Or{NotExists(Select("1").From("bans").Where("bans.user_id = users.id AND bans.level = ?", 2)), Eq{"admin": true}}).
+		PlaceholderFormat(Dollar)
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT id FROM users WHERE active = $1 " +
+		"AND EXISTS (SELECT 1 FROM orders WHERE orders.user_id = users.id AND orders.total > $2) " +
+		"AND (NOT EXISTS (SELECT 1 FROM bans WHERE bans.user_id = users.id AND bans.level = $3) OR admin = $4)"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{true, 100, 2, true}, args)
+}
+
+func TestConcatExpr(t *testing.T) {
+	b := ConcatExpr("SELECT * FROM t WHERE ", Expr("created > ?", 10), " AND ", Eq{"kind": "a", "id": []int{1, 2}}, " LIMIT 5")
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT * FROM t WHERE created > ? AND id IN (?,?) AND kind = ? LIMIT 5"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{10, 1, 2, "a"}, args)
+
+	_, _, err = ConcatExpr("a = ", 1).ToSql()
+	assert.Error(t, err)
+}
+
+func TestNotToSql(t *testing.T) {
+	tests := []struct {
+		pred         Sqlizer
+		expectedSql  string
+		expectedArgs []interface{}
+	}{
+		{Eq{"a": 1}, "NOT (a = ?)", []interface{}{1}},
+		{Or{Eq{"a": 1}, Eq{"b": 2}}, "NOT (a = ? OR b = ?)", []interface{}{1, 2}},
+		{Or{Expr("(a)"), Expr("(b)")}, "NOT ((a) OR (b))", nil},
+		{Expr("(a) OR (b)"), "NOT ((a) OR (b))", nil},
+		{Exists(Select("1").From("orders").Where("user_id = ?", 3)),
+			"NOT (EXISTS (SELECT 1 FROM orders WHERE user_id = ?))", []interface{}{3}},
+	}
+	for _, test := range tests {
+		sql, args, err := Not(test.pred).ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, test.expectedSql, sql)
+		assert.Equal(t, test.expectedArgs, args)
+	}
+
+	sql, args, err := Select("id").From("users").
+		Where(And{Eq{"active": true}, Not(Or{Eq{"role": "admin"}, Eq{"role": "owner"}})}).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE (active = ? AND NOT (role = ? OR role = ?))", sql)
+	assert.Equal(t, []interface{}{true, "admin", "owner"}, args)
+}
+
+func TestRaw(t *testing.T) {
+	sql, args, err := Select("id").From("sessions").Where(Eq{"expires_at": Raw("CURRENT_DATE"), "user_id": 7}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM sessions WHERE expires_at = CURRENT_DATE AND user_id = ?", sql)
+	assert.Equal(t, []interface{}{7}, args)
+
+	sql, args, err = Update("users").SetMap(map[string]interface{}{"name": "bob", "updated_at": Raw("NOW()")}).Where(Eq{"id": 1}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET name = ?, updated_at = NOW() WHERE id = ?", sql)
+	assert.Equal(t, []interface{}{"bob", 1}, args)
+
+	sql, args, err = Insert("users").Columns("name", "created_at").Values("bob", Raw("NOW()")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (name,created_at) VALUES (?,NOW())", sql)
+	assert.Equal(t, []interface{}{"bob"}, args)
+}
+
+func TestNamedExpr(t *testing.T) {
+	sql, args, err := Select("*").
+		From("tasks").
+		Where(NamedExpr("(owner_id = :user OR assignee_id = :user) AND state = :state", map[string]interface{}{"user": 7, "state": "open"})).
+		Where(NamedExpr("due < :due::date AND tag IN (:tags)", map[string]interface{}{"due": "2020-01-01", "tags": []string{"a", "b"}})).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM tasks WHERE (owner_id = $1 OR assignee_id = $2) AND state = $3 AND due < $4::date AND tag IN ($5,$6)", sql)
+	assert.Equal(t, []interface{}{7, 7, "open", "2020-01-01", "a", "b"}, args)
+
+	_, _, err = NamedExpr("id = :id", nil).ToSql()
+	assert.EqualError(t, err, "missing value for named arg :id")
+}
+
+func TestAnyOf(t *testing.T) {
+	sql, args, err := AnyOf(
+		map[string]interface{}{"b": 2, "a": 1},
+		map[string]interface{}{},
+		map[string]interface{}{"c": []int{3, 4}},
+	).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "((a = ? AND b = ?) OR c IN (?,?))", sql)
+	assert.Equal(t, []interface{}{1, 2, 3, 4}, args)
+
+	sql, _, err = Select("*").From("t").Where(AnyOf()).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t", sql)
+}
+
+func TestCast(t *testing.T) {
+	id := "0b6e3a48-5d5b-4f1e-9a43-6c2b8f1d9e7a"
+	sql, args, err := Select("id").
+		Column(Alias(Cast(Select("data").From("docs").Where("docs.owner = ?", 3), "jsonb"), "doc")).
+		Column(Cast("created_at", "date")).
+		From("users").
+		Where(Eq{"id": Cast(Expr("?", id), "uuid")}).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, ((SELECT data FROM docs WHERE docs.owner = $1)::jsonb) AS doc, created_at::date "+
+		"FROM users WHERE id = ($2)::uuid", sql)
+	assert.Equal(t, []interface{}{3, id}, args)
+
+	sql, args, err = Insert("events").
+		Columns("user_id", "kind", "n").
+		Values(Cast(Expr("?", id), "uuid"), Cast(Select("name").From("kinds").Where(Eq{"id": 1}), "text"), Cast(7, "bigint")).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO events (user_id,kind,n) VALUES (($1)::uuid,(SELECT name FROM kinds WHERE id = $2)::text,$3::bigint)", sql)
+	assert.Equal(t, []interface{}{id, 1, 7}, args)
+}
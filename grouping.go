@@ -0,0 +1,25 @@
+package bsql
+
+import "strings"
+
+// groupingSet renders a parenthesized list of grouping columns, e.g.
+// "(a, b)", or "()" for the grand total.
+func groupingSet(cols []string) string {
+	return "(" + strings.Join(cols, ", ") + ")"
+}
+
+func rollup(cols []string) string {
+	return "ROLLUP" + groupingSet(cols)
+}
+
+func cube(cols []string) string {
+	return "CUBE" + groupingSet(cols)
+}
+
+func groupingSets(sets [][]string) string {
+	rendered := make([]string, len(sets))
+	for i, set := range sets {
+		rendered[i] = groupingSet(set)
+	}
+	return "GROUPING SETS (" + strings.Join(rendered, ", ") + ")"
+}
@@ -0,0 +1,74 @@
+package bsql
+
+// clauseLimits holds optional upper bounds on the size of a query. A zero
+// limit means unlimited.
+type clauseLimits struct {
+	maxConditions int
+	maxJoins      int
+	maxColumns    int
+}
+
+func (l clauseLimits) check(conditions []Sqlizer, joins, columns int) error {
+	if l.maxJoins > 0 && joins > l.maxJoins {
+		return buildErrorf(CodeLimitExceeded, "query has %d joins, more than the maximum of %d", joins, l.maxJoins)
+	}
+	if l.maxColumns > 0 && columns > l.maxColumns {
+		return buildErrorf(CodeLimitExceeded, "query has %d columns, more than the maximum of %d", columns, l.maxColumns)
+	}
+	if l.maxConditions > 0 {
+		n := 0
+		for _, c := range conditions {
+			n += countConditions(c)
+		}
+		if n > l.maxConditions {
+			return buildErrorf(CodeLimitExceeded, "query has %d conditions, more than the maximum of %d", n, l.maxConditions)
+		}
+	}
+	return nil
+}
+
+// countConditions counts the leaf conditions of pred, descending into And/Or
+// trees, ConjBuilders and Not, and counting each key of map based predicates.
+func countConditions(pred interface{}) int {
+	switch p := pred.(type) {
+	case *wherePart:
+		return countConditions(p.pred)
+	case nil:
+		return 0
+	case And:
+		return countConj(conj(p))
+	case Or:
+		return countConj(conj(p))
+	case *ConjBuilder:
+		return countConj(conj(p.parts))
+	case not:
+		return countConditions(p.pred)
+	case map[string]interface{}:
+		return len(p)
+	case Eq:
+		return len(p)
+	case NotEq:
+		return len(p)
+	case Lt:
+		return len(p)
+	case LtOrEq:
+		return len(p)
+	case Gt:
+		return len(p)
+	case GtOrEq:
+		return len(p)
+	case Like:
+		return len(p)
+	case ILike:
+		return len(p)
+	}
+	return 1
+}
+
+func countConj(c conj) int {
+	n := 0
+	for _, p := range c {
+		n += countConditions(p)
+	}
+	return n
+}
@@ -2,9 +2,11 @@ package bsql
 
 import (
 	"bytes"
+	"context"
+	"database/sql"
 	"errors"
-	"fmt"
 	"io"
+	"reflect"
 	"strings"
 )
 
@@ -14,13 +16,22 @@ type InsertBuilder struct {
 
 	returning
 
+	comment  string
 	prefixes exprs
 	options  []string
+	ignore   bool
 	into     string
 	columns  []string
 	values   [][]interface{}
+	defaults bool
 	suffixes exprs
 	iselect  *SelectBuilder
+
+	onConflict          *onConflict
+	duplicateKeyUpdates setClauses
+	upsertKeys          []string
+
+	err error
 }
 
 // NewInsertBuilder creates new instance of InsertBuilder
@@ -28,6 +39,36 @@ func NewInsertBuilder(b StatementBuilderType) *InsertBuilder {
 	return &InsertBuilder{StatementBuilderType: b}
 }
 
+// Clone returns a copy of b that can be changed without affecting b.
+//
+// See SelectBuilder.Clone.
+func (b *InsertBuilder) Clone() *InsertBuilder {
+	c := *b
+	c.returning = c.returning[:len(c.returning):len(c.returning)]
+	c.prefixes = c.prefixes[:len(c.prefixes):len(c.prefixes)]
+	c.options = c.options[:len(c.options):len(c.options)]
+	c.columns = c.columns[:len(c.columns):len(c.columns)]
+	c.values = c.values[:len(c.values):len(c.values)]
+	c.suffixes = c.suffixes[:len(c.suffixes):len(c.suffixes)]
+	c.duplicateKeyUpdates = c.duplicateKeyUpdates[:len(c.duplicateKeyUpdates):len(c.duplicateKeyUpdates)]
+	c.upsertKeys = c.upsertKeys[:len(c.upsertKeys):len(c.upsertKeys)]
+	if c.onConflict != nil {
+		onConflict := *c.onConflict
+		onConflict.sets = onConflict.sets[:len(onConflict.sets):len(onConflict.sets)]
+		c.onConflict = &onConflict
+	}
+	return &c
+}
+
+// ForTable returns a copy of the query inserting into table instead.
+//
+// See SelectBuilder.ForTable.
+func (b *InsertBuilder) ForTable(table string) *InsertBuilder {
+	c := b.Clone()
+	c.into = table
+	return c
+}
+
 // PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
 // query.
 func (b *InsertBuilder) PlaceholderFormat(f PlaceholderFormat) *InsertBuilder {
@@ -35,25 +76,206 @@ func (b *InsertBuilder) PlaceholderFormat(f PlaceholderFormat) *InsertBuilder {
 	return b
 }
 
+// TagCaller makes ToSql append a "/* at file.go:123 */" comment naming the
+// code outside of bsql that built the query, to trace queries seen in
+// database logs back to their source. ToSql fails if no such caller is found.
+func (b *InsertBuilder) TagCaller(tag bool) *InsertBuilder {
+	b.tagCaller = tag
+	return b
+}
+
+// Quoting sets the QuoteStyle used for the table and column names of the
+// query.
+func (b *InsertBuilder) Quoting(style QuoteStyle) *InsertBuilder {
+	b.quoting = style
+	return b
+}
+
+// PlaceholderOffset makes positional placeholders start at n+1.
+//
+// See SelectBuilder.PlaceholderOffset.
+func (b *InsertBuilder) PlaceholderOffset(n int) *InsertBuilder {
+	b.placeholderOffset = n
+	return b
+}
+
+// StrictArgs makes ToSql fail when the number of placeholders in the query
+// does not match the number of args.
+//
+// See SelectBuilder.StrictArgs.
+func (b *InsertBuilder) StrictArgs(strict bool) *InsertBuilder {
+	b.strictArgs = strict
+	return b
+}
+
+// PreEvalValuers makes ToSql replace driver.Valuer args by their value.
+//
+// See SelectBuilder.PreEvalValuers.
+func (b *InsertBuilder) PreEvalValuers(eval bool) *InsertBuilder {
+	b.preEvalValuers = eval
+	return b
+}
+
+// NormalizeNils makes ToSql replace nil pointer args by nil.
+//
+// See SelectBuilder.NormalizeNils.
+func (b *InsertBuilder) NormalizeNils(normalize bool) *InsertBuilder {
+	b.normalizeNils = normalize
+	return b
+}
+
+// BoolAsInt makes ToSql bind bool args as 1 and 0.
+//
+// See SelectBuilder.BoolAsInt.
+func (b *InsertBuilder) BoolAsInt(asInt bool) *InsertBuilder {
+	b.boolAsInt = asInt
+	return b
+}
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+//
+// *sql.DB and *sql.Tx are wrapped automatically.
+func (b *InsertBuilder) RunWith(runner BaseRunner) *InsertBuilder {
+	b.runner = wrapRunner(runner)
+	return b
+}
+
+// Exec builds and executes the query with the Runner set by RunWith.
+func (b *InsertBuilder) Exec() (sql.Result, error) {
+	return exec(b.runner, b)
+}
+
+// Query builds and executes the query with the Runner set by RunWith.
+func (b *InsertBuilder) Query() (*sql.Rows, error) {
+	return query(b.runner, b)
+}
+
+// QueryRow builds and executes the query with the Runner set by RunWith.
+// The Runner must implement QueryRower.
+func (b *InsertBuilder) QueryRow() RowScanner {
+	return queryRow(b.runner, b)
+}
+
+// ExecContext builds and executes the query with the Runner set by RunWith.
+// The Runner must implement ExecerContext.
+func (b *InsertBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
+	return execContext(ctx, b.runner, b)
+}
+
+// QueryContext builds and executes the query with the Runner set by RunWith.
+// The Runner must implement QueryerContext.
+func (b *InsertBuilder) QueryContext(ctx context.Context) (*sql.Rows, error) {
+	return queryContext(ctx, b.runner, b)
+}
+
+// QueryRowContext builds and executes the query with the Runner set by
+// RunWith. The Runner must implement QueryRowerContext.
+func (b *InsertBuilder) QueryRowContext(ctx context.Context) RowScanner {
+	return queryRowContext(ctx, b.runner, b)
+}
+
+// ExecReturning executes the query with the Runner set by RunWith and scans
+// the row produced by its RETURNING clause into dest, e.g. to read generated
+// keys. It returns ErrNoReturning if Returning was not called.
+func (b *InsertBuilder) ExecReturning(ctx context.Context, dest ...interface{}) error {
+	return b.returning.execReturning(ctx, b.runner, b, dest...)
+}
+
+// ToNamedSql builds a single-row insert with a named placeholder per column,
+// "@column", for APIs that bind args by name, such as pgx named args. It
+// returns the values by column name instead of positional args.
+// Ex:
+//     Insert("users").Columns("name", "age").Values("bob", 30).ToNamedSql()
+//     == "INSERT INTO users (name,age) VALUES (@name,@age)", {"name": "bob", "age": 30}
+//
+// Sqlizer values are inlined as with ToSql, but must not have args. Inserts
+// without columns, of several rows, or with args outside the values cannot
+// be named and return an error, as do named columns that are not plain
+// identifiers, such as "t.name". A column named twice is bound once if both
+// values are equal and is an error otherwise, as they would share a name.
+func (b *InsertBuilder) ToNamedSql() (string, map[string]interface{}, error) {
+	if len(b.columns) == 0 {
+		return "", nil, buildErrorf(CodeInvalidClause, "named insert statements must specify columns")
+	}
+	if len(b.values) != 1 {
+		return "", nil, buildErrorf(CodeInvalidClause, "named insert statements must have exactly one row of values, got %d", len(b.values))
+	}
+
+	named := make(map[string]interface{}, len(b.columns))
+	row := make([]interface{}, len(b.values[0]))
+	for i, val := range b.values[0] {
+		if _, ok := val.(Sqlizer); ok || i >= len(b.columns) {
+			row[i] = val
+			continue
+		}
+		if !isPlainIdentifier(b.columns[i]) {
+			return "", nil, buildErrorf(CodeInvalidClause, "named insert column %q is not a plain identifier", b.columns[i])
+		}
+		if prev, ok := named[b.columns[i]]; ok && !reflect.DeepEqual(prev, val) {
+			return "", nil, buildErrorf(CodeInvalidClause, "named insert column %q has two different values", b.columns[i])
+		}
+		named[b.columns[i]] = val
+		row[i] = Raw("@" + b.columns[i])
+	}
+
+	c := b.Clone()
+	c.values = [][]interface{}{row}
+	sql, args, err := c.ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+	if len(args) > 0 {
+		return "", nil, buildErrorf(CodeInvalidClause, "named insert statements cannot have positional args, got %d", len(args))
+	}
+	return sql, named, nil
+}
+
+// isPlainIdentifier reports whether name is a letter or underscore followed
+// by letters, digits and underscores.
+func isPlainIdentifier(name string) bool {
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && r >= '0' && r <= '9':
+		default:
+			return false
+		}
+	}
+	return name != ""
+}
+
 // ToSql builds the query into a SQL string and bound args.
 func (b *InsertBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
-	if len(b.into) == 0 {
-		err = fmt.Errorf("insert statements must specify a table")
+	if err = b.validate(); err != nil {
 		return
 	}
-	if len(b.values) == 0 && b.iselect == nil {
-		err = fmt.Errorf("insert statements must have at least one set of values or select clause")
-		return
+	if b.upsertKeys != nil && b.flavor == flavorSQLServer {
+		return b.mergeSql()
 	}
 
 	sql := &bytes.Buffer{}
 
 	if len(b.prefixes) > 0 {
-		args, _ = b.prefixes.AppendToSql(sql, " ", args)
+		args, err = b.prefixes.AppendToSql(sql, " ", args)
+		if err != nil {
+			return
+		}
 		sql.WriteString(" ")
 	}
 
 	sql.WriteString("INSERT ")
+	appendComment(sql, b.comment)
+
+	if b.ignore {
+		switch b.flavor {
+		case flavorSQLite:
+			sql.WriteString("OR IGNORE ")
+		case flavorPostgres:
+			// rendered as ON CONFLICT DO NOTHING below
+		default:
+			sql.WriteString("IGNORE ")
+		}
+	}
 
 	if len(b.options) > 0 {
 		sql.WriteString(strings.Join(b.options, " "))
@@ -61,24 +283,53 @@ func (b *InsertBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 	}
 
 	sql.WriteString("INTO ")
-	sql.WriteString(b.into)
+	sql.WriteString(b.quoting.Quote(b.into))
 	sql.WriteString(" ")
 
-	if len(b.columns) > 0 {
+	if b.defaultsOnly() {
+		sql.WriteString("DEFAULT VALUES")
+	} else if len(b.columns) > 0 {
 		sql.WriteString("(")
-		sql.WriteString(strings.Join(b.columns, ","))
+		for i, column := range b.columns {
+			if i > 0 {
+				sql.WriteString(",")
+			}
+			sql.WriteString(b.quoting.Quote(column))
+		}
 		sql.WriteString(") ")
 	}
 
 	if b.iselect != nil {
 		args, err = b.appendSelectToSQL(sql, args)
-	} else {
+	} else if !b.defaultsOnly() {
 		args, err = b.appendValuesToSQL(sql, args)
 	}
 	if err != nil {
 		return
 	}
 
+	conflict, duplicateKeyUpdates := b.onConflict, b.duplicateKeyUpdates
+	if b.upsertKeys != nil {
+		conflict, duplicateKeyUpdates = b.upsertClauses()
+	}
+
+	if conflict != nil {
+		args, err = conflict.AppendToSql(sql, args)
+		if err != nil {
+			return
+		}
+	} else if b.ignore && b.flavor == flavorPostgres {
+		sql.WriteString(" ON CONFLICT DO NOTHING")
+	}
+
+	if len(duplicateKeyUpdates) > 0 {
+		sql.WriteString(" ON DUPLICATE KEY UPDATE ")
+		args, err = duplicateKeyUpdates.AppendToSql(sql, ", ", args)
+		if err != nil {
+			return
+		}
+	}
+
 	if len(b.returning) > 0 {
 		args, err = b.returning.AppendToSql(sql, args)
 		if err != nil {
@@ -88,60 +339,96 @@ func (b *InsertBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 
 	if len(b.suffixes) > 0 {
 		sql.WriteString(" ")
-		args, _ = b.suffixes.AppendToSql(sql, " ", args)
+		args, err = b.suffixes.AppendToSql(sql, " ", args)
+		if err != nil {
+			return
+		}
 	}
 
-	sqlStr, err = b.placeholderFormat.ReplacePlaceholders(sql.String())
+	sqlStr, args, err = b.finalize(sql.String(), args)
 	return
 }
 
-func (b *InsertBuilder) appendValuesToSQL(w io.Writer, args []interface{}) ([]interface{}, error) {
-	if len(b.values) == 0 {
-		return args, errors.New("values for insert statements are not set")
-	}
-
-	io.WriteString(w, "VALUES ")
-
-	valuesStrings := make([]string, len(b.values))
-	for r, row := range b.values {
-		valueStrings := make([]string, len(row))
-		for v, val := range row {
-
-			switch typedVal := val.(type) {
-			case expr:
-				valueStrings[v] = typedVal.sql
-				args = append(args, typedVal.args...)
-			case Sqlizer:
-				var valSql string
-				var valArgs []interface{}
-				var err error
-
-				valSql, valArgs, err = typedVal.ToSql()
-				if err != nil {
-					return nil, err
-				}
-
-				valueStrings[v] = valSql
-				args = append(args, valArgs...)
-			default:
-				valueStrings[v] = "?"
-				args = append(args, val)
+// validate reports all structural problems of the query at once.
+func (b *InsertBuilder) validate() error {
+	var errs []error
+	if b.err != nil {
+		errs = append(errs, b.err)
+	}
+	if len(b.into) == 0 {
+		errs = append(errs, buildErrorf(CodeNoTable, "insert statements must specify a table"))
+	}
+	if len(b.values) == 0 && b.iselect == nil && !b.defaults {
+		errs = append(errs, buildErrorf(CodeNoValues, "insert statements must have at least one set of values or select clause"))
+	}
+	if len(b.columns) > 0 {
+		for i, row := range b.values {
+			if len(row) != len(b.columns) {
+				errs = append(errs, buildErrorf(CodeArgMismatch, "insert values row %d has %d values, expected %d for the columns", i+1, len(row), len(b.columns)))
+				break
 			}
 		}
-		valuesStrings[r] = fmt.Sprintf("(%s)", strings.Join(valueStrings, ","))
 	}
+	if b.onConflict != nil && len(b.duplicateKeyUpdates) > 0 {
+		errs = append(errs, buildErrorf(CodeInvalidClause, "insert statements cannot have both ON CONFLICT and ON DUPLICATE KEY UPDATE clauses"))
+	}
+	if b.ignore && b.flavor == flavorSQLServer {
+		errs = append(errs, buildErrorf(CodeInvalidClause, "insert ignore is not supported by SQL Server"))
+	}
+	if b.ignore && b.flavor == flavorPostgres && (b.onConflict != nil || len(b.duplicateKeyUpdates) > 0) {
+		errs = append(errs, buildErrorf(CodeInvalidClause, "insert ignore cannot be combined with another conflict clause under Postgres"))
+	}
+	if b.upsertKeys != nil {
+		errs = append(errs, b.validateUpsert()...)
+	}
+	return errors.Join(errs...)
+}
+
+// validateUpsert reports the problems of an AsUpsert insert.
+func (b *InsertBuilder) validateUpsert() (errs []error) {
+	if len(b.upsertKeys) == 0 {
+		errs = append(errs, buildErrorf(CodeInvalidClause, "upsert statements must specify key columns"))
+	}
+	if len(b.columns) == 0 {
+		errs = append(errs, buildErrorf(CodeNoColumns, "upsert statements must specify columns"))
+	}
+	columns := make(map[string]bool, len(b.columns))
+	for _, column := range b.columns {
+		columns[column] = true
+	}
+	for _, key := range b.upsertKeys {
+		if !columns[key] {
+			errs = append(errs, buildErrorf(CodeInvalidClause, "upsert key column %q is not an insert column", key))
+		}
+	}
+	if b.onConflict != nil || len(b.duplicateKeyUpdates) > 0 || b.ignore {
+		errs = append(errs, buildErrorf(CodeInvalidClause, "upsert statements cannot have ON CONFLICT, ON DUPLICATE KEY UPDATE or IGNORE clauses"))
+	}
+	if b.flavor == flavorSQLServer && len(b.returning) > 0 {
+		errs = append(errs, buildErrorf(CodeInvalidClause, "upsert statements cannot have RETURNING under SQL Server"))
+	}
+	return errs
+}
 
-	io.WriteString(w, strings.Join(valuesStrings, ","))
+// defaultsOnly reports whether the query inserts a row of DEFAULT VALUES.
+func (b *InsertBuilder) defaultsOnly() bool {
+	return b.defaults && len(b.values) == 0 && b.iselect == nil
+}
 
-	return args, nil
+func (b *InsertBuilder) appendValuesToSQL(w io.Writer, args []interface{}) ([]interface{}, error) {
+	if len(b.values) == 0 {
+		return args, buildErrorf(CodeNoValues, "values for insert statements are not set")
+	}
+
+	return valuesList(b.values).AppendToSql(w, args)
 }
 
 func (b *InsertBuilder) appendSelectToSQL(w io.Writer, args []interface{}) ([]interface{}, error) {
 	if b.iselect == nil {
-		return args, errors.New("select clause for insert statements are not set")
+		return args, buildErrorf(CodeNoValues, "select clause for insert statements are not set")
 	}
 
-	selectClause, sArgs, err := b.iselect.ToSql()
+	selectClause, sArgs, err := b.iselect.toSqlRaw()
 	if err != nil {
 		return args, err
 	}
@@ -152,6 +439,13 @@ func (b *InsertBuilder) appendSelectToSQL(w io.Writer, args []interface{}) ([]in
 	return args, nil
 }
 
+// Comment adds a "/* text */" comment right after the INSERT keyword, e.g. to
+// tag queries for monitoring. Comment delimiters in text are neutralized.
+func (b *InsertBuilder) Comment(text string) *InsertBuilder {
+	b.comment = text
+	return b
+}
+
 // Prefix adds an expression to the beginning of the query
 func (b *InsertBuilder) Prefix(sql string, args ...interface{}) *InsertBuilder {
 	b.prefixes = append(b.prefixes, Expr(sql, args...))
@@ -164,6 +458,22 @@ func (b *InsertBuilder) Options(options ...string) *InsertBuilder {
 	return b
 }
 
+// Ignore makes the insert skip rows that would violate a unique constraint
+// instead of failing. The keyword depends on the Dialect:
+//
+//   INSERT IGNORE INTO ...                  -- MySQL and the default
+//   INSERT OR IGNORE INTO ...               -- SQLite
+//   INSERT INTO ... ON CONFLICT DO NOTHING  -- Postgres
+//
+// MySQL's IGNORE also downgrades other errors, such as invalid values, to
+// warnings; SQLite and Postgres only skip conflicting rows. SQL Server has no
+// equivalent and ToSql returns an error, as does Postgres when OnConflict or
+// OnDuplicateKeyUpdate is also set.
+func (b *InsertBuilder) Ignore() *InsertBuilder {
+	b.ignore = true
+	return b
+}
+
 // Into sets the INTO clause of the query.
 func (b *InsertBuilder) Into(into string) *InsertBuilder {
 	b.into = into
@@ -182,6 +492,49 @@ func (b *InsertBuilder) Values(values ...interface{}) *InsertBuilder {
 	return b
 }
 
+// ValuesBatch adds rows to the query, as calling Values for each row would,
+// growing the list of rows once.
+func (b *InsertBuilder) ValuesBatch(rows [][]interface{}) *InsertBuilder {
+	if cap(b.values)-len(b.values) < len(rows) {
+		grown := make([][]interface{}, len(b.values), len(b.values)+len(rows))
+		copy(grown, b.values)
+		b.values = grown
+	}
+	b.values = append(b.values, rows...)
+	return b
+}
+
+// ValuesTyped adds rows of a single value type to b, as ValuesBatch does.
+// The values are converted to interface{} into one allocation shared by all
+// rows, rather than one per row.
+//
+//   ValuesTyped(Insert("points").Columns("x", "y"), [][]float64{{1, 2}, {3, 4}})
+func ValuesTyped[T any](b *InsertBuilder, rows [][]T) *InsertBuilder {
+	n := 0
+	for _, row := range rows {
+		n += len(row)
+	}
+	vals := make([]interface{}, n)
+	batch := make([][]interface{}, len(rows))
+	for i, row := range rows {
+		boxed := vals[:len(row):len(row)]
+		vals = vals[len(row):]
+		for j, v := range row {
+			boxed[j] = v
+		}
+		batch[i] = boxed
+	}
+	return b.ValuesBatch(batch)
+}
+
+// DefaultValues makes the query insert a single row of column defaults, as
+// "INSERT INTO t DEFAULT VALUES", e.g. to reserve a generated id. It is
+// ignored when Values or Select are used.
+func (b *InsertBuilder) DefaultValues() *InsertBuilder {
+	b.defaults = true
+	return b
+}
+
 // Returning adds columns to RETURNING clause of the query
 //
 // INSERT ... RETURNING is PostgreSQL specific extension
@@ -190,6 +543,16 @@ func (b *InsertBuilder) Returning(columns ...string) *InsertBuilder {
 	return b
 }
 
+// ReturningAll sets a RETURNING * clause, returning all columns of the
+// affected rows. It replaces the columns added by Returning and
+// ReturningSelect, and is replaced by the ones added after it.
+//
+// INSERT ... RETURNING is PostgreSQL specific extension
+func (b *InsertBuilder) ReturningAll() *InsertBuilder {
+	b.returning.ReturningAll()
+	return b
+}
+
 // ReturningSelect adds subquery to RETURNING clause of the query
 //
 // INSERT ... RETURNING is PostgreSQL specific extension
@@ -206,14 +569,16 @@ func (b *InsertBuilder) Suffix(sql string, args ...interface{}) *InsertBuilder {
 
 // SetMap set columns and values for insert builder from a map of column name and value
 // note that it will reset all previous columns and values was set if any
+//
+// Columns are added in sorted order, so the generated SQL is stable.
 func (b *InsertBuilder) SetMap(clauses map[string]interface{}) *InsertBuilder {
 	// TODO: replace resetting previous values with extending existing ones?
 	cols := make([]string, 0, len(clauses))
 	vals := make([]interface{}, 0, len(clauses))
 
-	for col, val := range clauses {
+	for _, col := range sortedKeys(clauses) {
 		cols = append(cols, col)
-		vals = append(vals, val)
+		vals = append(vals, clauses[col])
 	}
 
 	b.columns = cols
@@ -221,6 +586,85 @@ func (b *InsertBuilder) SetMap(clauses map[string]interface{}) *InsertBuilder {
 	return b
 }
 
+// SetMaps sets columns and values for a multi-row insert from rows, one map
+// of column name and value per row. Like SetMap, it resets any previously set
+// columns and values.
+//
+// The columns are the sorted union of the keys of all rows, and a row
+// missing one of them gets DEFAULT for it, so that the column default
+// applies. Use SetMapsFill to insert another value, like nil for NULL.
+func (b *InsertBuilder) SetMaps(rows []map[string]interface{}) *InsertBuilder {
+	return b.SetMapsFill(rows, Expr("DEFAULT"))
+}
+
+// SetMapsFill is like SetMaps, but inserts fill for the columns a row is
+// missing.
+func (b *InsertBuilder) SetMapsFill(rows []map[string]interface{}, fill interface{}) *InsertBuilder {
+	union := map[string]interface{}{}
+	for _, row := range rows {
+		for col := range row {
+			union[col] = nil
+		}
+	}
+	cols := sortedKeys(union)
+
+	values := make([][]interface{}, len(rows))
+	for i, row := range rows {
+		vals := make([]interface{}, len(cols))
+		for j, col := range cols {
+			val, ok := row[col]
+			if !ok {
+				val = fill
+			}
+			vals[j] = val
+		}
+		values[i] = vals
+	}
+
+	b.columns = cols
+	b.values = values
+	return b
+}
+
+// SetMapOmitNil is like SetMap, but skips the entries whose value is nil or a
+// nil pointer so that the column defaults apply. Zero values such as 0 or ""
+// are kept.
+func (b *InsertBuilder) SetMapOmitNil(clauses map[string]interface{}) *InsertBuilder {
+	return b.SetMap(withoutNils(clauses))
+}
+
+// SetStruct sets columns from the exported fields of v, a struct or pointer
+// to struct, and appends their values as a row. Calling it repeatedly with
+// values of the same type builds a multi-row insert.
+//
+// Fields map to columns by their `db` tag, in declaration order; untagged
+// fields are skipped:
+//
+//   type User struct {
+//       ID    int64  `db:"-"`
+//       Name  string `db:"name"`
+//       Email string `db:"email,omitempty"`
+//   }
+//
+// An error for a non-struct v, or for a row whose columns differ from the
+// columns already set or the rows already added, is returned by ToSql.
+func (b *InsertBuilder) SetStruct(v interface{}) *InsertBuilder {
+	cols, vals, err := structValues(v)
+	if err != nil {
+		b.err = err
+		return b
+	}
+	if (len(b.columns) > 0 || len(b.values) > 0) && strings.Join(cols, ",") != strings.Join(b.columns, ",") {
+		b.err = buildErrorf(CodeArgMismatch, "struct columns (%s) do not match insert columns (%s)",
+			strings.Join(cols, ", "), strings.Join(b.columns, ", "))
+		return b
+	}
+
+	b.columns = cols
+	b.values = append(b.values, vals)
+	return b
+}
+
 // Select set Select clause for insert query
 // If Values and Select are used, then Select has higher priority
 func (b *InsertBuilder) Select(sb *SelectBuilder) *InsertBuilder {
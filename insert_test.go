@@ -0,0 +1,576 @@
+package bsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertBuilderOnConflictDoUpdate(t *testing.T) {
+	b := Insert("stock").
+		Columns("sku", "qty").
+		Values("a", 1).
+		Values("b", 2).
+		OnConflict("sku").
+		DoUpdateSet(map[string]interface{}{"updated_by": "import"}).
+		DoUpdateSetExpr("qty", "stock.qty + EXCLUDED.qty + ?", 10).
+		Returning("id").
+		PlaceholderFormat(Dollar)
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "INSERT INTO stock (sku,qty) VALUES ($1,$2),($3,$4) " +
+		"ON CONFLICT (sku) DO UPDATE SET updated_by = $5, qty = stock.qty + EXCLUDED.qty + $6 " +
+		"RETURNING id"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{"a", 1, "b", 2, "import", 10}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestInsertBuilderOnConflictDoNothing(t *testing.T) {
+	b := Insert("stock").Columns("sku").Values("a").OnConflict().DoNothing()
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "INSERT INTO stock (sku) VALUES (?) ON CONFLICT DO NOTHING"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{"a"}, args)
+
+	_, _, err = Insert("stock").Columns("sku").Values("a").OnConflict("sku").ToSql()
+	assert.Error(t, err)
+}
+
+func TestInsertBuilderOnDuplicateKeyUpdate(t *testing.T) {
+	b := Insert("stock").
+		Columns("sku", "qty", "note").
+		Values("a", 1, "x").
+		Values("b", 2, "y").
+		OnDuplicateKeyUpdate(map[string]interface{}{
+			"qty":  Expr("VALUES(qty)"),
+			"note": "updated",
+		}).
+		OnDuplicateKeyUpdateExpr("hits", "hits + ?", 1)
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "INSERT INTO stock (sku,qty,note) VALUES (?,?,?),(?,?,?) " +
+		"ON DUPLICATE KEY UPDATE note = ?, qty = VALUES(qty), hits = hits + ?"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{"a", 1, "x", "b", 2, "y", "updated", 1}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestInsertBuilderSetMapStable(t *testing.T) {
+	clauses := map[string]interface{}{"e": 5, "b": 2, "d": 4, "a": 1, "c": 3}
+	for i := 0; i < 20; i++ {
+		sql, args, err := Insert("t").SetMap(clauses).ToSql()
+		assert.NoError(t, err)
+
+		assert.Equal(t, "INSERT INTO t (a,b,c,d,e) VALUES (?,?,?,?,?)", sql)
+		assert.Equal(t, []interface{}{1, 2, 3, 4, 5}, args)
+	}
+}
+
+func TestInsertBuilderRunContext(t *testing.T) {
+	db := &DBStub{err: StubError}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "insert")
+	b := Insert("t").Columns("a").Values(1).RunWith(db)
+
+	_, err := b.ExecContext(ctx)
+	assert.Equal(t, StubError, err)
+	assert.Equal(t, ctx, db.LastContext)
+	assert.Equal(t, "INSERT INTO t (a) VALUES (?)", db.LastExecSql)
+	assert.Equal(t, []interface{}{1}, db.LastExecArgs)
+}
+
+func TestInsertBuilderExecReturning(t *testing.T) {
+	db := &DBStub{row: []interface{}{int64(7)}}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "returning")
+	b := Insert("users").Columns("name").Values("bob").Returning("id").
+		PlaceholderFormat(Dollar).RunWith(db)
+
+	var id int64
+	err := b.ExecReturning(ctx, &id)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), id)
+	assert.Equal(t, ctx, db.LastContext)
+	assert.Equal(t, "INSERT INTO users (name) VALUES ($1) RETURNING id", db.LastQueryRowSql)
+	assert.Equal(t, []interface{}{"bob"}, db.LastQueryRowArgs)
+}
+
+func TestInsertBuilderExecReturningWithoutReturning(t *testing.T) {
+	db := &DBStub{}
+	var id int64
+	err := Insert("users").Columns("name").Values("bob").RunWith(db).ExecReturning(context.Background(), &id)
+	assert.Equal(t, ErrNoReturning, err)
+	assert.Empty(t, db.LastQueryRowSql)
+}
+
+func TestInsertBuilderRun(t *testing.T) {
+	db := &DBStub{}
+	_, err := Insert("t").Columns("a").Values(1).RunWith(db).Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t (a) VALUES (?)", db.LastExecSql)
+	assert.Equal(t, []interface{}{1}, db.LastExecArgs)
+}
+
+type auditFields struct {
+	CreatedBy string `db:"created_by"`
+	Note      string `db:"note,omitempty"`
+}
+
+type userRow struct {
+	ID    int64  `db:"-"`
+	Name  string `db:"name"`
+	Email string `db:"email,omitempty"`
+	Age   int
+	auditFields
+
+	secret string
+}
+
+func TestInsertBuilderSetStruct(t *testing.T) {
+	u := userRow{ID: 1, Name: "bob", Age: 30, auditFields: auditFields{CreatedBy: "admin"}, secret: "x"}
+	sql, args, err := Insert("users").SetStruct(&u).ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "INSERT INTO users (name,created_by) VALUES (?,?)"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{"bob", "admin"}, args)
+
+	u.Email = "bob@example.com"
+	sql, args, err = Insert("users").SetStruct(u).ToSql()
+	assert.NoError(t, err)
+
+	expectedSql = "INSERT INTO users (name,email,created_by) VALUES (?,?,?)"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{"bob", "bob@example.com", "admin"}, args)
+}
+
+func TestInsertBuilderSetStructRows(t *testing.T) {
+	b := Insert("users").
+		SetStruct(userRow{Name: "a", Age: 1}).
+		SetStruct(userRow{Name: "b", Age: 2})
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (name,created_by) VALUES (?,?),(?,?)", sql)
+	assert.Equal(t, []interface{}{"a", "", "b", ""}, args)
+
+	_, _, err = Insert("users").
+		SetStruct(userRow{Name: "a"}).
+		SetStruct(userRow{Name: "b", Email: "b@example.com"}).
+		ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Insert("users").
+		Columns("email", "name").
+		SetStruct(userRow{Name: "a"}).
+		ToSql()
+	assert.ErrorIs(t, err, ErrArgMismatch)
+}
+
+func TestInsertBuilderSetStructNotStruct(t *testing.T) {
+	_, _, err := Insert("users").SetStruct(map[string]interface{}{"a": 1}).ToSql()
+	assert.Error(t, err)
+
+	var u *userRow
+	_, _, err = Insert("users").SetStruct(u).ToSql()
+	assert.Error(t, err)
+}
+
+func TestInsertBuilderForTable(t *testing.T) {
+	b := Insert("events_0").Columns("a").Values(1)
+	sql, args, err := b.ForTable("events_1").Values(2).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO events_1 (a) VALUES (?),(?)", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+
+	sql, _, err = b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO events_0 (a) VALUES (?)", sql)
+}
+
+func TestInsertBuilderIgnore(t *testing.T) {
+	tests := []struct {
+		dialect     Dialect
+		expectedSql string
+	}{
+		{MySQL, "INSERT IGNORE INTO `users` (`id`) VALUES (?)"},
+		{SQLite, `INSERT OR IGNORE INTO "users" ("id") VALUES (?)`},
+		{Postgres, `INSERT INTO "users" ("id") VALUES ($1) ON CONFLICT DO NOTHING`},
+	}
+	for _, test := range tests {
+		sql, args, err := StatementBuilder.Dialect(test.dialect).
+			Insert("users").Ignore().Columns("id").Values(1).ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, test.expectedSql, sql)
+		assert.Equal(t, []interface{}{1}, args)
+	}
+
+	sql, _, err := Insert("users").Ignore().Values(1).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT IGNORE INTO users VALUES (?)", sql)
+
+	_, _, err = StatementBuilder.Dialect(SQLServer).Insert("users").Ignore().Values(1).ToSql()
+	assert.Error(t, err)
+}
+
+func TestInsertBuilderIgnorePostgresConflict(t *testing.T) {
+	sb := StatementBuilder.Dialect(Postgres)
+
+	_, _, err := sb.Insert("users").Ignore().Columns("id").Values(1).
+		OnConflict("id").DoNothing().ToSql()
+	assert.ErrorIs(t, err, ErrInvalidClause)
+
+	_, _, err = sb.Insert("users").Ignore().Columns("id", "n").Values(1, 2).
+		OnDuplicateKeyUpdate(map[string]interface{}{"n": 3}).ToSql()
+	assert.ErrorIs(t, err, ErrInvalidClause)
+}
+
+func TestInsertBuilderSetMapOmitNil(t *testing.T) {
+	var nilName *string
+	sql, args, err := Insert("users").SetMapOmitNil(map[string]interface{}{
+		"name":  nilName,
+		"email": nil,
+		"age":   0,
+		"bio":   "",
+	}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (age,bio) VALUES (?,?)", sql)
+	assert.Equal(t, []interface{}{0, ""}, args)
+}
+
+func TestInsertBuilderDefaultValues(t *testing.T) {
+	sql, args, err := Insert("").Into("t").DefaultValues().Returning("id").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t DEFAULT VALUES RETURNING id", sql)
+	assert.Empty(t, args)
+
+	sql, args, err = Insert("t").DefaultValues().Columns("a").Values(1).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t (a) VALUES (?)", sql)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestInsertBuilderValidationErrors(t *testing.T) {
+	_, _, err := Insert("").ToSql()
+	assert.EqualError(t, err, "insert statements must specify a table\n"+
+		"insert statements must have at least one set of values or select clause")
+
+	_, _, err = Insert("t").Values(1).
+		OnConflict("id").DoNothing().
+		OnDuplicateKeyUpdate(map[string]interface{}{"a": 1}).
+		ToSql()
+	assert.EqualError(t, err, "insert statements cannot have both ON CONFLICT and ON DUPLICATE KEY UPDATE clauses")
+}
+
+func TestInsertBuilderValuesLength(t *testing.T) {
+	_, _, err := Insert("t").Columns("a", "b").Values(1, 2).Values(3).ToSql()
+	assert.EqualError(t, err, "insert values row 2 has 1 values, expected 2 for the columns")
+
+	_, _, err = Insert("t").Columns("a", "b").Values(1, 2, 3).ToSql()
+	assert.EqualError(t, err, "insert values row 1 has 3 values, expected 2 for the columns")
+
+	_, _, err = Insert("t").Values(1, 2, 3).ToSql()
+	assert.NoError(t, err)
+}
+
+func TestInsertBuilderStrictArgs(t *testing.T) {
+	_, _, err := StatementBuilder.StrictArgs(true).
+		Insert("t").Columns("a").Values(Expr("? + ?", 1)).ToSql()
+	assert.EqualError(t, err, "query has 2 placeholders but 1 args")
+}
+
+func TestInsertBuilderCloneOnConflict(t *testing.T) {
+	base := Insert("users").Columns("name").Values("bob")
+	base.OnConflict("name").DoUpdateSet(map[string]interface{}{"a": 1})
+	clone := base.Clone()
+	(&OnConflictBuilder{clone}).DoUpdateSetExpr("b", "b + 1")
+
+	sql, _, err := base.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (name) VALUES (?) ON CONFLICT (name) DO UPDATE SET a = ?", sql)
+
+	sql, _, err = clone.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (name) VALUES (?) ON CONFLICT (name) DO UPDATE SET a = ?, b = b + 1", sql)
+}
+
+func TestInsertBuilderValuesMixedTypes(t *testing.T) {
+	sub := Select("id").From("kinds").Where(Eq{"name": "click"})
+	sql, args, err := Insert("events").Columns("id", "kind", "payload", "at", "note").
+		Values(1, sub, []byte("{}"), Expr("NOW()"), nil).
+		Values(int64(2), Expr("?", 3), []byte(nil), Expr("? + interval '1 day'", "2024-01-01"), "x").
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "INSERT INTO events (id,kind,payload,at,note) VALUES " +
+		"($1,(SELECT id FROM kinds WHERE name = $2),$3,NOW(),$4)," +
+		"($5,$6,$7,$8 + interval '1 day',$9)"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{1, "click", []byte("{}"), nil, int64(2), 3, []byte(nil), "2024-01-01", "x"}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func BenchmarkInsertBuilderValues10k(b *testing.B) {
+	q := Insert("events").Columns("id", "kind", "payload", "at")
+	for i := 0; i < 10000; i++ {
+		q.Values(i, "click", []byte("{}"), Expr("NOW()"))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q.ToSql()
+	}
+}
+
+func TestInsertBuilderValuesBatch(t *testing.T) {
+	rows := [][]int{{1, 2}, {3, 4}, {5, 6}}
+	single := Insert("points").Columns("x", "y")
+	batch := make([][]interface{}, len(rows))
+	for i, row := range rows {
+		single.Values(row[0], row[1])
+		batch[i] = []interface{}{row[0], row[1]}
+	}
+	expectedSql, expectedArgs, err := single.ToSql()
+	assert.NoError(t, err)
+
+	sql, args, err := Insert("points").Columns("x", "y").ValuesBatch(batch).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, expectedArgs, args)
+
+	sql, args, err = ValuesTyped(Insert("points").Columns("x", "y").Values(0, 0), rows).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO points (x,y) VALUES (?,?),(?,?),(?,?),(?,?)", sql)
+	assert.Equal(t, []interface{}{0, 0, 1, 2, 3, 4, 5, 6}, args)
+}
+
+func BenchmarkInsertBuilderValuesTyped50k(b *testing.B) {
+	rows := make([][]int64, 50000)
+	for i := range rows {
+		rows[i] = []int64{int64(i), int64(i) * 2, int64(i) * 3}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ValuesTyped(Insert("points").Columns("x", "y", "z"), rows).ToSql()
+	}
+}
+
+type statusValuer string
+
+func (s statusValuer) Value() (driver.Value, error) {
+	switch s {
+	case "active":
+		return int64(1), nil
+	case "inactive":
+		return int64(0), nil
+	}
+	return nil, fmt.Errorf("invalid status %q", string(s))
+}
+
+func TestInsertBuilderPreEvalValuers(t *testing.T) {
+	b := Insert("users").Columns("name", "status").Values("bob", statusValuer("active"))
+
+	_, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"bob", statusValuer("active")}, args)
+
+	_, args, err = b.PreEvalValuers(true).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"bob", int64(1)}, args)
+
+	_, _, err = Insert("users").Columns("name", "status").Values("bob", statusValuer("gone")).
+		PreEvalValuers(true).ToSql()
+	assert.EqualError(t, err, `arg 2: invalid status "gone"`)
+}
+
+func TestInsertBuilderReturningAll(t *testing.T) {
+	sql, _, err := Insert("users").Columns("name").Values("bob").
+		Returning("id").ReturningAll().PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (name) VALUES ($1) RETURNING *", sql)
+}
+
+func TestInsertBuilderSetMaps(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"name": "alice", "age": 30},
+		{"name": "bob", "email": "bob@example.com"},
+		{"age": 40, "email": "carol@example.com", "name": "carol"},
+	}
+
+	sql, args, err := Insert("users").SetMaps(rows).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (age,email,name) VALUES "+
+		"(?,DEFAULT,?),(DEFAULT,?,?),(?,?,?)", sql)
+	assert.Equal(t, []interface{}{30, "alice", "bob@example.com", "bob", 40, "carol@example.com", "carol"}, args)
+
+	sql, args, err = Insert("users").SetMapsFill(rows[:2], nil).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (age,email,name) VALUES (?,?,?),(?,?,?)", sql)
+	assert.Equal(t, []interface{}{30, nil, "alice", nil, "bob@example.com", "bob"}, args)
+}
+
+func TestInsertBuilderSubqueryValue(t *testing.T) {
+	sql, args, err := Insert("orders").
+		Columns("customer_id", "total").
+		Values(Select("id").From("customers").Where(Eq{"email": "a@example.com"}), 42).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO orders (customer_id,total) VALUES ((SELECT id FROM customers WHERE email = $1),$2)", sql)
+	assert.Equal(t, []interface{}{"a@example.com", 42}, args)
+}
+
+func TestInsertBuilderNormalizeNils(t *testing.T) {
+	var (
+		age    *int
+		email  *string
+		status *statusValuer
+	)
+	name := "bob"
+	b := Insert("users").Columns("name", "age", "email", "status").Values(&name, age, email, status)
+
+	_, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{&name, age, email, status}, args)
+
+	_, args, err = b.NormalizeNils(true).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{&name, nil, nil, status}, args)
+	assert.Same(t, &name, args[0])
+}
+
+func TestInsertBuilderToNamedSql(t *testing.T) {
+	sql, named, err := Insert("users").
+		Columns("name", "age", "created_at").
+		Values("bob", 30, Raw("NOW()")).
+		Suffix("RETURNING id").
+		PlaceholderFormat(Dollar).
+		ToNamedSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (name,age,created_at) VALUES (@name,@age,NOW()) RETURNING id", sql)
+	assert.Equal(t, map[string]interface{}{"name": "bob", "age": 30}, named)
+
+	_, _, err = Insert("users").Columns("name").Values("bob").Values("alice").ToNamedSql()
+	assert.EqualError(t, err, "named insert statements must have exactly one row of values, got 2")
+
+	_, _, err = Insert("users").Columns("name").Values("bob").Suffix("ON CONFLICT DO UPDATE SET n = ?", 1).ToNamedSql()
+	assert.EqualError(t, err, "named insert statements cannot have positional args, got 1")
+}
+
+func TestInsertBuilderToNamedSqlDuplicateNames(t *testing.T) {
+	_, _, err := Insert("users").Columns("name", "email", "name").Values("bob", "b@x", "alice").ToNamedSql()
+	assert.ErrorIs(t, err, ErrInvalidClause)
+	assert.EqualError(t, err, `named insert column "name" has two different values`)
+
+	sql, named, err := Insert("users").Columns("name", "name").Values("bob", "bob").ToNamedSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (name,name) VALUES (@name,@name)", sql)
+	assert.Equal(t, map[string]interface{}{"name": "bob"}, named)
+}
+
+func TestInsertBuilderToNamedSqlInvalidNames(t *testing.T) {
+	for _, column := range []string{"u.name", "first name", `"name"`, "1st", ""} {
+		_, _, err := Insert("users").Columns(column).Values("bob").ToNamedSql()
+		assert.ErrorIs(t, err, ErrInvalidClause, column)
+	}
+
+	sql, _, err := Insert("users").Columns("_name2", "u.created_at").Values("bob", Raw("NOW()")).ToNamedSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (_name2,u.created_at) VALUES (@_name2,NOW())", sql)
+}
+
+func TestInsertBuilderAsUpsert(t *testing.T) {
+	tests := []struct {
+		dialect     Dialect
+		expectedSql string
+	}{
+		{Postgres, `INSERT INTO "users" ("id","name","age") VALUES ($1,$2,$3),($4,$5,$6) ` +
+			`ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name", "age" = EXCLUDED."age"`},
+		{MySQL, "INSERT INTO `users` (`id`,`name`,`age`) VALUES (?,?,?),(?,?,?) " +
+			"ON DUPLICATE KEY UPDATE `name` = VALUES(`name`), `age` = VALUES(`age`)"},
+		{SQLServer, "MERGE INTO [users] AS target USING (VALUES (@p1,@p2,@p3),(@p4,@p5,@p6)) " +
+			"AS source ([id],[name],[age]) ON target.[id] = source.[id] " +
+			"WHEN MATCHED THEN UPDATE SET target.[name] = source.[name], target.[age] = source.[age] " +
+			"WHEN NOT MATCHED THEN INSERT ([id],[name],[age]) VALUES (source.[id],source.[name],source.[age]);"},
+	}
+	for _, test := range tests {
+		sql, args, err := StatementBuilder.Dialect(test.dialect).
+			Insert("users").
+			Columns("id", "name", "age").
+			Values(1, "bob", 30).
+			Values(2, "alice", 40).
+			AsUpsert([]string{"id"}).
+			ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, test.expectedSql, sql)
+		assert.Equal(t, []interface{}{1, "bob", 30, 2, "alice", 40}, args)
+	}
+}
+
+func TestInsertBuilderAsUpsertSQLServer(t *testing.T) {
+	sb := StatementBuilder.Dialect(SQLServer).Quoting(QuoteStyle{})
+
+	sql, args, err := sb.Insert("stock").
+		Comment("sync").
+		Columns("shop", "sku", "qty").
+		Select(Select("shop", "sku", "qty").From("incoming").Where(Eq{"batch": 7})).
+		AsUpsert([]string{"shop", "sku"}).
+		ToSql()
+	assert.NoError(t, err)
+	expectedSql := "MERGE /* sync */ INTO stock AS target USING (SELECT shop, sku, qty FROM incoming WHERE batch = @p1) " +
+		"AS source (shop,sku,qty) ON target.shop = source.shop AND target.sku = source.sku " +
+		"WHEN MATCHED THEN UPDATE SET target.qty = source.qty " +
+		"WHEN NOT MATCHED THEN INSERT (shop,sku,qty) VALUES (source.shop,source.sku,source.qty);"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{7}, args)
+
+	sql, _, err = sb.Insert("tags").Columns("name").Values("go").AsUpsert([]string{"name"}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "MERGE INTO tags AS target USING (VALUES (@p1)) AS source (name) ON target.name = source.name "+
+		"WHEN NOT MATCHED THEN INSERT (name) VALUES (source.name);", sql)
+
+	sql, _, err = sb.Quoting(BracketQuotes).Insert("tags").Columns("name").Values("go").AsUpsert([]string{"name"}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "MERGE INTO [tags] AS target USING (VALUES (@p1)) AS source ([name]) ON target.[name] = source.[name] "+
+		"WHEN NOT MATCHED THEN INSERT ([name]) VALUES (source.[name]);", sql)
+
+	_, _, err = sb.Insert("users").Columns("id").Values(1).AsUpsert([]string{"id"}).Returning("id").ToSql()
+	assert.ErrorIs(t, err, ErrInvalidClause)
+}
+
+func TestInsertBuilderAsUpsertKeysOnly(t *testing.T) {
+	sql, _, err := Insert("tags").Columns("name").Values("go").AsUpsert([]string{"name"}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO tags (name) VALUES (?) ON CONFLICT (name) DO NOTHING", sql)
+
+	sql, _, err = StatementBuilder.Dialect(MySQL).Quoting(QuoteStyle{}).
+		Insert("tags").Columns("name").Values("go").AsUpsert([]string{"name"}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO tags (name) VALUES (?) ON DUPLICATE KEY UPDATE name = VALUES(name)", sql)
+}
+
+func TestInsertBuilderAsUpsertErrors(t *testing.T) {
+	_, _, err := Insert("users").Columns("id").Values(1).AsUpsert(nil).ToSql()
+	assert.ErrorIs(t, err, ErrInvalidClause)
+
+	_, _, err = Insert("users").Columns("id").Values(1).AsUpsert([]string{"email"}).ToSql()
+	assert.EqualError(t, err, `upsert key column "email" is not an insert column`)
+
+	_, _, err = Insert("users").Values(1).AsUpsert([]string{"id"}).ToSql()
+	assert.ErrorIs(t, err, ErrNoColumns)
+
+	_, _, err = Insert("users").Columns("id").Values(1).AsUpsert([]string{"id"}).OnConflict("id").DoNothing().ToSql()
+	assert.ErrorIs(t, err, ErrInvalidClause)
+}
@@ -0,0 +1,27 @@
+package bsql
+
+import (
+	"io"
+	"strings"
+)
+
+// rowLock is the locking clause of a select statement, e.g.
+// "FOR UPDATE OF jobs SKIP LOCKED".
+type rowLock struct {
+	strength string
+	of       []string
+	wait     string
+}
+
+func (l rowLock) AppendToSql(w io.Writer) {
+	io.WriteString(w, " FOR ")
+	io.WriteString(w, l.strength)
+	if len(l.of) > 0 {
+		io.WriteString(w, " OF ")
+		io.WriteString(w, strings.Join(l.of, ", "))
+	}
+	if l.wait != "" {
+		io.WriteString(w, " ")
+		io.WriteString(w, l.wait)
+	}
+}
@@ -0,0 +1,113 @@
+package bsql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// QueryLogger is called after each query run by a runner created with
+// WithLogger, with the SQL, args, duration and error of the query.
+type QueryLogger func(ctx context.Context, sql string, args []interface{}, dur time.Duration, err error)
+
+type loggingRunner struct {
+	runner BaseRunner
+	logger QueryLogger
+}
+
+// WithLogger wraps runner so that logger is called after every query it runs,
+// including failed ones, e.g. to log slow queries or record metrics:
+//
+//   db := bsql.WithLogger(sqlDB, func(ctx context.Context, sql string, args []interface{}, dur time.Duration, err error) {
+//       log.Printf("%s %v took %s: %v", sql, args, dur, err)
+//   })
+//   Select("*").From("users").RunWith(db).QueryContext(ctx)
+//
+// The methods without a context pass context.Background() to logger. For
+// QueryRow and QueryRowContext, logger is called by Scan of the returned row.
+// The results of the runner are returned unchanged.
+func WithLogger(runner BaseRunner, logger QueryLogger) RunnerContext {
+	return &loggingRunner{runner: wrapRunner(runner), logger: logger}
+}
+
+func (r *loggingRunner) log(ctx context.Context, start time.Time, query string, args []interface{}, err error) {
+	r.logger(ctx, query, args, time.Since(start), err)
+}
+
+func (r *loggingRunner) Exec(query string, args ...interface{}) (sql.Result, error) {
+	start := time.Now()
+	res, err := r.runner.Exec(query, args...)
+	r.log(context.Background(), start, query, args, err)
+	return res, err
+}
+
+func (r *loggingRunner) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := r.runner.Query(query, args...)
+	r.log(context.Background(), start, query, args, err)
+	return rows, err
+}
+
+func (r *loggingRunner) QueryRow(query string, args ...interface{}) RowScanner {
+	start := time.Now()
+	queryRower, ok := r.runner.(QueryRower)
+	if !ok {
+		r.log(context.Background(), start, query, args, ErrRunnerNotQueryRunner)
+		return &Row{err: ErrRunnerNotQueryRunner}
+	}
+	return &loggedRow{
+		RowScanner: queryRower.QueryRow(query, args...),
+		log: func(err error) {
+			r.log(context.Background(), start, query, args, err)
+		},
+	}
+}
+
+func (r *loggingRunner) ExecContext(ctx context.Context, query string, args ...interface{}) (res sql.Result, err error) {
+	start := time.Now()
+	if execer, ok := r.runner.(ExecerContext); ok {
+		res, err = execer.ExecContext(ctx, query, args...)
+	} else {
+		err = ErrNoContextSupport
+	}
+	r.log(ctx, start, query, args, err)
+	return
+}
+
+func (r *loggingRunner) QueryContext(ctx context.Context, query string, args ...interface{}) (rows *sql.Rows, err error) {
+	start := time.Now()
+	if queryer, ok := r.runner.(QueryerContext); ok {
+		rows, err = queryer.QueryContext(ctx, query, args...)
+	} else {
+		err = ErrNoContextSupport
+	}
+	r.log(ctx, start, query, args, err)
+	return
+}
+
+func (r *loggingRunner) QueryRowContext(ctx context.Context, query string, args ...interface{}) RowScanner {
+	start := time.Now()
+	queryRower, ok := r.runner.(QueryRowerContext)
+	if !ok {
+		r.log(ctx, start, query, args, ErrRunnerNotQueryRunner)
+		return &Row{err: ErrRunnerNotQueryRunner}
+	}
+	return &loggedRow{
+		RowScanner: queryRower.QueryRowContext(ctx, query, args...),
+		log: func(err error) {
+			r.log(ctx, start, query, args, err)
+		},
+	}
+}
+
+// loggedRow calls log with the error of Scan.
+type loggedRow struct {
+	RowScanner
+	log func(err error)
+}
+
+func (r *loggedRow) Scan(dest ...interface{}) error {
+	err := r.RowScanner.Scan(dest...)
+	r.log(err)
+	return err
+}
@@ -0,0 +1,72 @@
+package bsql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type loggedQuery struct {
+	ctx  context.Context
+	sql  string
+	args []interface{}
+	dur  time.Duration
+	err  error
+}
+
+func recordQueries(logged *[]loggedQuery) QueryLogger {
+	return func(ctx context.Context, sql string, args []interface{}, dur time.Duration, err error) {
+		*logged = append(*logged, loggedQuery{ctx, sql, args, dur, err})
+	}
+}
+
+func TestWithLogger(t *testing.T) {
+	var logged []loggedQuery
+	db := &DBStub{err: StubError}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "logged")
+	runner := WithLogger(db, recordQueries(&logged))
+
+	_, err := Update("users").Set("a", 1).Where(Eq{"id": 2}).RunWith(runner).ExecContext(ctx)
+	assert.Equal(t, StubError, err)
+	_, err = Select("id").From("users").Where(Eq{"team": 3}).RunWith(runner).QueryContext(ctx)
+	assert.Equal(t, StubError, err)
+
+	if assert.Len(t, logged, 2) {
+		assert.Equal(t, ctx, logged[0].ctx)
+		assert.Equal(t, "UPDATE users SET a = ? WHERE id = ?", logged[0].sql)
+		assert.Equal(t, []interface{}{1, 2}, logged[0].args)
+		assert.Equal(t, StubError, logged[0].err)
+		assert.Equal(t, "SELECT id FROM users WHERE team = ?", logged[1].sql)
+		assert.Equal(t, []interface{}{3}, logged[1].args)
+		assert.Equal(t, StubError, logged[1].err)
+	}
+	assert.Equal(t, "UPDATE users SET a = ? WHERE id = ?", db.LastExecSql)
+}
+
+func TestWithLoggerQueryRow(t *testing.T) {
+	var logged []loggedQuery
+	db := &DBStub{row: []interface{}{5}}
+	runner := WithLogger(db, recordQueries(&logged))
+
+	var n int
+	err := Select("count(*)").From("users").RunWith(runner).ScanContext(context.Background(), &n)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, n)
+	if assert.Len(t, logged, 1) {
+		assert.Equal(t, "SELECT count(*) FROM users", logged[0].sql)
+		assert.NoError(t, logged[0].err)
+	}
+}
+
+func TestWithLoggerNoContextSupport(t *testing.T) {
+	var logged []loggedQuery
+	runner := WithLogger(&baseRunnerStub{}, recordQueries(&logged))
+
+	_, err := Delete("users").RunWith(runner).ExecContext(context.Background())
+	assert.Equal(t, ErrNoContextSupport, err)
+	if assert.Len(t, logged, 1) {
+		assert.Equal(t, ErrNoContextSupport, logged[0].err)
+	}
+}
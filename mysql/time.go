@@ -0,0 +1,43 @@
+// package mysql provides MySQL specific Sqlizers.
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/langbox/bsql"
+)
+
+var bucketFormats = map[string]string{
+	"second": "%Y-%m-%d %H:%i:%s",
+	"minute": "%Y-%m-%d %H:%i:00",
+	"hour":   "%Y-%m-%d %H:00:00",
+	"day":    "%Y-%m-%d 00:00:00",
+	"month":  "%Y-%m-01 00:00:00",
+	"year":   "%Y-01-01 00:00:00",
+}
+
+// TimeBucket truncates a datetime column to the given interval with
+// DATE_FORMAT, e.g. TimeBucket("created_at", "hour") renders
+// DATE_FORMAT(created_at, '%Y-%m-%d %H:00:00').
+//
+// Supported intervals are second, minute, hour, day, month and year. The
+// format is rendered inline rather than bound, so that the same expression
+// can be repeated in the select list and in GROUP BY.
+func TimeBucket(column, interval string) bsql.Sqlizer {
+	return timeBucket{column, interval}
+}
+
+type timeBucket struct {
+	column   string
+	interval string
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (tb timeBucket) ToSql() (string, []interface{}, error) {
+	format, ok := bucketFormats[tb.interval]
+	if !ok {
+		return "", nil, fmt.Errorf("Unsupported time bucket interval %q", tb.interval)
+	}
+
+	return fmt.Sprintf("DATE_FORMAT(%s, '%s')", tb.column, format), nil, nil
+}
@@ -0,0 +1,19 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeBucket(t *testing.T) {
+	sql, args, err := TimeBucket("created_at", "hour").ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "DATE_FORMAT(created_at, '%Y-%m-%d %H:00:00')"
+	assert.Equal(t, expectedSql, sql)
+	assert.Empty(t, args)
+
+	_, _, err = TimeBucket("created_at", "week").ToSql()
+	assert.Error(t, err)
+}
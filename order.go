@@ -0,0 +1,74 @@
+package bsql
+
+// OrderTerm is an ORDER BY item built by Asc or Desc.
+type OrderTerm struct {
+	column string
+	desc   bool
+	nulls  string
+}
+
+// Asc sorts by column in ascending order.
+// Ex:
+//     .OrderByTerms(Asc("name"), Desc("created_at").NullsLast())
+func Asc(column string) OrderTerm {
+	return OrderTerm{column: column}
+}
+
+// Desc sorts by column in descending order.
+func Desc(column string) OrderTerm {
+	return OrderTerm{column: column, desc: true}
+}
+
+// NullsFirst sorts NULL values before all other values.
+func (t OrderTerm) NullsFirst() OrderTerm {
+	t.nulls = "FIRST"
+	return t
+}
+
+// NullsLast sorts NULL values after all other values.
+func (t OrderTerm) NullsLast() OrderTerm {
+	t.nulls = "LAST"
+	return t
+}
+
+// ToSql builds the term into a SQL string, e.g. "created_at DESC NULLS LAST".
+func (t OrderTerm) ToSql() (string, []interface{}, error) {
+	return t.render(QuoteStyle{}, flavorDefault), nil, nil
+}
+
+// render renders the term with the column quoted by q. MySQL has no NULLS
+// FIRST/LAST, so it sorts by "column IS NULL" first instead.
+func (t OrderTerm) render(q QuoteStyle, f flavor) string {
+	column := q.quoteName(t.column)
+
+	sql := column + " ASC"
+	if t.desc {
+		sql = column + " DESC"
+	}
+
+	switch {
+	case t.nulls == "":
+	case f == flavorMySQL:
+		isNull := column + " IS NULL"
+		if t.nulls == "FIRST" {
+			isNull += " DESC"
+		}
+		sql = isNull + ", " + sql
+	default:
+		sql += " NULLS " + t.nulls
+	}
+	return sql
+}
+
+// orderByParts returns the parts of an ORDER BY clause with identifiers
+// quoted by q and OrderTerms rendered for flavor f.
+func orderByParts(parts []Sqlizer, q QuoteStyle, f flavor) []Sqlizer {
+	rendered := make([]Sqlizer, len(parts))
+	for i, p := range quoteIdentifiers(parts, q) {
+		if t, ok := p.(OrderTerm); ok {
+			p = newPart(t.render(q, f))
+		}
+		rendered[i] = p
+	}
+	return rendered
+}
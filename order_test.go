@@ -0,0 +1,35 @@
+package bsql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderTerms(t *testing.T) {
+	build := func(sb StatementBuilderType) *SelectBuilder {
+		return sb.Select("id").From("users").
+			OrderBy("team").
+			OrderByTerms(Desc("last_login").NullsLast(), Asc("name").NullsFirst(), Asc("id"))
+	}
+
+	sql, _, err := build(StatementBuilder).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users ORDER BY team, last_login DESC NULLS LAST, name ASC NULLS FIRST, id ASC", sql)
+
+	sql, _, err = build(StatementBuilder.Dialect(Postgres)).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT "id" FROM users ORDER BY "team", "last_login" DESC NULLS LAST, "name" ASC NULLS FIRST, "id" ASC`, sql)
+
+	sql, _, err = build(StatementBuilder.Dialect(MySQL)).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT `id` FROM users ORDER BY `team`, "+
+		"`last_login` IS NULL, `last_login` DESC, `name` IS NULL DESC, `name` ASC, `id` ASC", sql)
+}
+
+func TestOrderTermToSql(t *testing.T) {
+	sql, args, err := Desc("created_at").NullsFirst().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "created_at DESC NULLS FIRST", sql)
+	assert.Empty(t, args)
+}
@@ -1,6 +1,7 @@
 package bsql
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 )
@@ -19,10 +20,9 @@ func (p part) ToSql() (sql string, args []interface{}, err error) {
 	case nil:
 		// no-op
 	case Sqlizer:
-		sql, args, err = pred.ToSql()
+		sql, args, err = nestedToSql(pred)
 	case string:
-		sql = pred
-		args = p.args
+		return Expr(pred, p.args...).ToSql()
 	default:
 		err = fmt.Errorf("expected string or Sqlizer, not %T", pred)
 	}
@@ -30,7 +30,8 @@ func (p part) ToSql() (sql string, args []interface{}, err error) {
 }
 
 func appendToSql(parts []Sqlizer, w io.Writer, sep string, args []interface{}) ([]interface{}, error) {
-	for i, p := range parts {
+	written := 0
+	for _, p := range parts {
 		partSql, partArgs, err := p.ToSql()
 		if err != nil {
 			return nil, err
@@ -38,7 +39,7 @@ func appendToSql(parts []Sqlizer, w io.Writer, sep string, args []interface{}) (
 			continue
 		}
 
-		if i > 0 {
+		if written > 0 {
 			_, err := io.WriteString(w, sep)
 			if err != nil {
 				return nil, err
@@ -50,6 +51,23 @@ func appendToSql(parts []Sqlizer, w io.Writer, sep string, args []interface{}) (
 			return nil, err
 		}
 		args = append(args, partArgs...)
+		written++
+	}
+	return args, nil
+}
+
+// appendClauseToSql writes keyword followed by parts joined by sep, or nothing
+// at all when every part renders empty.
+func appendClauseToSql(parts []Sqlizer, w io.Writer, keyword, sep string, args []interface{}) ([]interface{}, error) {
+	buf := &bytes.Buffer{}
+	args, err := appendToSql(parts, buf, sep, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if buf.Len() > 0 {
+		io.WriteString(w, keyword)
+		buf.WriteTo(w)
 	}
 	return args, nil
 }
@@ -0,0 +1,105 @@
+package pg
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/langbox/bsql"
+)
+
+// Any renders "column = ANY(?)", binding values (a slice or array) as a
+// single Postgres array argument. Unlike bsql.Eq with a slice, which expands
+// to one placeholder per element, the number of parameters stays constant
+// no matter how many values are matched.
+func Any(column string, values interface{}) bsql.Sqlizer {
+	return anyOp{column: column, opr: "=", quantifier: "ANY", values: values}
+}
+
+type anyOp struct {
+	column     string
+	opr        string
+	quantifier string
+	values     interface{}
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (a anyOp) ToSql() (string, []interface{}, error) {
+	if err := checkArrayType(a.values); err != nil {
+		return "", nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	marshalArray(reflect.ValueOf(a.values), buf)
+	return fmt.Sprintf("%s %s %s(?)", a.column, a.opr, a.quantifier), []interface{}{buf.String()}, nil
+}
+
+// Eq is bsql.Eq preferring Postgres arrays: slice values render as
+// "col = ANY(?)" bound as one array argument instead of an expanded IN list.
+// Other values render as with bsql.Eq. The PreferAnyArray builder option does
+// the same for bsql.Eq but binds the slice as is; Eq binds an array literal,
+// which drivers that do not accept slices, like lib/pq, can bind too.
+type Eq map[string]interface{}
+
+// ToSql builds the query into a SQL string and bound args.
+func (eq Eq) ToSql() (string, []interface{}, error) {
+	return eqToSql(eq, false)
+}
+
+// NotEq is the negation of Eq: slice values render as "col <> ALL(?)".
+type NotEq Eq
+
+// ToSql builds the query into a SQL string and bound args.
+func (neq NotEq) ToSql() (string, []interface{}, error) {
+	return eqToSql(neq, true)
+}
+
+func eqToSql(eq map[string]interface{}, not bool) (string, []interface{}, error) {
+	keys := make([]string, 0, len(eq))
+	for key := range eq {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var (
+		exprs []string
+		args  []interface{}
+	)
+	for _, key := range keys {
+		var pred bsql.Sqlizer
+		switch val := eq[key]; {
+		case isArray(val) && not:
+			pred = anyOp{column: key, opr: "<>", quantifier: "ALL", values: val}
+		case isArray(val):
+			pred = Any(key, val)
+		case not:
+			pred = bsql.NotEq{key: val}
+		default:
+			pred = bsql.Eq{key: val}
+		}
+
+		predSql, predArgs, err := pred.ToSql()
+		if err != nil {
+			return "", nil, err
+		}
+		exprs = append(exprs, predSql)
+		args = append(args, predArgs...)
+	}
+	return strings.Join(exprs, " AND "), args, nil
+}
+
+// isArray reports whether val should be bound as a Postgres array. []byte
+// and driver.Valuer values are left to the driver.
+func isArray(val interface{}) bool {
+	if _, ok := val.(driver.Valuer); ok {
+		return false
+	}
+	if _, ok := val.([]byte); ok {
+		return false
+	}
+	k := reflect.ValueOf(val).Kind()
+	return k == reflect.Slice || k == reflect.Array
+}
@@ -0,0 +1,43 @@
+package pg
+
+import (
+	"testing"
+
+	"github.com/langbox/bsql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnyComparedToIn(t *testing.T) {
+	ids := []int{1, 2, 3}
+
+	sql, args, err := bsql.Select("name").From("users").
+		Where(bsql.Eq{"id": ids}).
+		PlaceholderFormat(bsql.Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT name FROM users WHERE id IN ($1,$2,$3)", sql)
+	assert.Equal(t, []interface{}{1, 2, 3}, args)
+
+	sql, args, err = bsql.Select("name").From("users").
+		Where(Any("id", ids)).
+		PlaceholderFormat(bsql.Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT name FROM users WHERE id = ANY($1)", sql)
+	assert.Equal(t, []interface{}{"{1,2,3}"}, args)
+}
+
+func TestEq(t *testing.T) {
+	sql, args, err := Eq{"id": []int{1, 2}, "state": "active", "deleted_at": nil}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "deleted_at IS NULL AND id = ANY(?) AND state = ?", sql)
+	assert.Equal(t, []interface{}{"{1,2}", "active"}, args)
+
+	sql, args, err = NotEq{"name": []string{"a", "b"}, "data": []byte("x")}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "data <> ? AND name <> ALL(?)", sql)
+	assert.Equal(t, []interface{}{[]byte("x"), `{"a","b"}`}, args)
+
+	_, _, err = Any("id", 1).ToSql()
+	assert.Error(t, err)
+}
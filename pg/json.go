@@ -3,6 +3,7 @@ package pg
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
 
 	"github.com/langbox/bsql"
 )
@@ -37,3 +38,63 @@ func (jo jsonOp) ToSql() (string, []interface{}, error) {
 
 	return fmt.Sprintf("?::%s", jo.tpe), []interface{}{string(v)}, nil
 }
+
+// JSONExtract renders "column -> ?", extracting the JSON object field or
+// array element key (a string or int) as JSON. The key is cast to text or
+// int, so that Postgres can tell the two operators apart.
+func JSONExtract(column string, key interface{}) bsql.Sqlizer {
+	return jsonPathOp{column: column, opr: "->", path: key}
+}
+
+// JSONExtractText renders "column ->> ?", extracting key as text.
+func JSONExtractText(column string, key interface{}) bsql.Sqlizer {
+	return jsonPathOp{column: column, opr: "->>", path: key}
+}
+
+// JSONPath renders "column #> ?::text[]", extracting the JSON value at path,
+// bound as a Postgres text array.
+func JSONPath(column string, path ...string) bsql.Sqlizer {
+	return jsonPathOp{column: column, opr: "#>", path: path}
+}
+
+// JSONPathText renders "column #>> ?::text[]", extracting the value at path as text.
+func JSONPathText(column string, path ...string) bsql.Sqlizer {
+	return jsonPathOp{column: column, opr: "#>>", path: path}
+}
+
+// JSONContains renders "column @> ?::jsonb", testing whether column contains
+// value serialized as JSON.
+func JSONContains(column string, value interface{}) bsql.Sqlizer {
+	return bsql.Expr(column+" @> ?", JSONB(value))
+}
+
+type jsonPathOp struct {
+	column string
+	opr    string
+	path   interface{}
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (jp jsonPathOp) ToSql() (string, []interface{}, error) {
+	path, cast := jp.path, ""
+	switch reflect.ValueOf(path).Kind() {
+	case reflect.Slice:
+		elems, ok := path.([]string)
+		if !ok {
+			return "", nil, fmt.Errorf("JSON path must be a []string, got %T", path)
+		}
+		_, args, err := Array(elems).ToSql()
+		if err != nil {
+			return "", nil, err
+		}
+		path, cast = args[0], "text[]"
+	case reflect.String:
+		cast = "text"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		cast = "int"
+	default:
+		return "", nil, fmt.Errorf("JSON key must be a string or an integer, got %T", path)
+	}
+	return fmt.Sprintf("%s %s ?::%s", jp.column, jp.opr, cast), []interface{}{path}, nil
+}
@@ -0,0 +1,53 @@
+package pg
+
+import (
+	"testing"
+
+	"github.com/langbox/bsql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONExtract(t *testing.T) {
+	b := bsql.Select("id").
+		Column(JSONExtractText("data", "name")).
+		Column(JSONPath("data", "address", "city")).
+		From("nodes").
+		Where(bsql.Eq{"kind": "user"}).
+		Where(bsql.Expr("? = ?", JSONExtract("data", 0), "x")).
+		Where(bsql.Expr("? IS NOT NULL", JSONPathText("data", "tags"))).
+		Where("data ??| array['a', 'b']").
+		PlaceholderFormat(bsql.Dollar)
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT id, data ->> $1::text, data #> $2::text[] FROM nodes " +
+		"WHERE kind = $3 AND data -> $4::int = $5 AND data #>> $6::text[] IS NOT NULL AND data ?| array['a', 'b']"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{"name", `{"address","city"}`, "user", 0, "x", `{"tags"}`}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestJSONExtractKeyTypes(t *testing.T) {
+	sql, args, err := JSONExtract("data", uint8(2)).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "data -> ?::int", sql)
+	assert.Equal(t, []interface{}{uint8(2)}, args)
+
+	sql, _, err = JSONExtractText("data", "0").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "data ->> ?::text", sql)
+
+	_, _, err = JSONExtract("data", 1.5).ToSql()
+	assert.EqualError(t, err, "JSON key must be a string or an integer, got float64")
+}
+
+func TestJSONContains(t *testing.T) {
+	b := bsql.Select("id").From("nodes").
+		Where(JSONContains("data", map[string]interface{}{"tags": []string{"a"}})).
+		PlaceholderFormat(bsql.Dollar)
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM nodes WHERE data @> $1::jsonb", sql)
+	assert.Equal(t, []interface{}{`{"tags":["a"]}`}, args)
+}
@@ -0,0 +1,47 @@
+package pg
+
+import (
+	"fmt"
+
+	"github.com/langbox/bsql"
+)
+
+var truncUnits = map[string]bool{
+	"microseconds": true,
+	"milliseconds": true,
+	"second":       true,
+	"minute":       true,
+	"hour":         true,
+	"day":          true,
+	"week":         true,
+	"month":        true,
+	"quarter":      true,
+	"year":         true,
+	"decade":       true,
+	"century":      true,
+	"millennium":   true,
+}
+
+// TimeBucket truncates a timestamp column to the given interval with
+// date_trunc, e.g. TimeBucket("created_at", "hour") renders
+// date_trunc('hour', created_at).
+//
+// The interval is rendered inline rather than bound, so that the same
+// expression can be repeated in the select list and in GROUP BY.
+func TimeBucket(column, interval string) bsql.Sqlizer {
+	return timeBucket{column, interval}
+}
+
+type timeBucket struct {
+	column   string
+	interval string
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (tb timeBucket) ToSql() (string, []interface{}, error) {
+	if !truncUnits[tb.interval] {
+		return "", nil, fmt.Errorf("Unsupported date_trunc interval %q", tb.interval)
+	}
+
+	return fmt.Sprintf("date_trunc('%s', %s)", tb.interval, tb.column), nil, nil
+}
@@ -0,0 +1,22 @@
+package pg
+
+import (
+	"testing"
+
+	"github.com/langbox/bsql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeBucket(t *testing.T) {
+	bucket := TimeBucket("created_at", "hour")
+	b := bsql.Select().Column(bucket).Column("COUNT(*)").From("events").GroupBy("1")
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT date_trunc('hour', created_at), COUNT(*) FROM events GROUP BY 1"
+	assert.Equal(t, expectedSql, sql)
+	assert.Empty(t, args)
+
+	_, _, err = TimeBucket("created_at", "fortnight").ToSql()
+	assert.Error(t, err)
+}
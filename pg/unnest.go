@@ -0,0 +1,54 @@
+package pg
+
+import (
+	"reflect"
+
+	"github.com/langbox/bsql"
+)
+
+// Unnest renders "unnest(?::castType) AS alias", expanding the array arg
+// into rows, for use with SelectBuilder.FromClause or JoinClause. arg is
+// bound as one parameter: a slice or array is converted as with Array, and
+// any other value, like a driver.Valuer, is bound as is. castType may be
+// empty to omit the cast.
+//
+// With withOrdinality set, "WITH ORDINALITY" adds the 1-based position of
+// each element as a second column, which alias can name:
+//
+//   bsql.Select("t.val", "t.idx").FromClause(Unnest(ids, "int[]", "t(val, idx)", true))
+//   // SELECT t.val, t.idx FROM unnest(?::int[]) WITH ORDINALITY AS t(val, idx)
+func Unnest(arg interface{}, castType string, alias string, withOrdinality bool) bsql.Sqlizer {
+	return unnest{arg: arg, castType: castType, alias: alias, withOrdinality: withOrdinality}
+}
+
+type unnest struct {
+	arg            interface{}
+	castType       string
+	alias          string
+	withOrdinality bool
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (u unnest) ToSql() (string, []interface{}, error) {
+	arg := u.arg
+	if k := reflect.ValueOf(arg).Kind(); k == reflect.Slice || k == reflect.Array {
+		_, args, err := Array(arg).ToSql()
+		if err != nil {
+			return "", nil, err
+		}
+		arg = args[0]
+	}
+
+	sql := "unnest(?"
+	if u.castType != "" {
+		sql += "::" + u.castType
+	}
+	sql += ")"
+	if u.withOrdinality {
+		sql += " WITH ORDINALITY"
+	}
+	if u.alias != "" {
+		sql += " AS " + u.alias
+	}
+	return sql, []interface{}{arg}, nil
+}
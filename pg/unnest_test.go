@@ -0,0 +1,30 @@
+package pg
+
+import (
+	"testing"
+
+	"github.com/langbox/bsql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnnest(t *testing.T) {
+	sql, args, err := bsql.Select("t.val", "t.idx").
+		FromClause(Unnest([]int{10, 20, 30}, "int[]", "t(val, idx)", true)).
+		Where("t.val > ?", 15).
+		PlaceholderFormat(bsql.Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT t.val, t.idx FROM unnest($1::int[]) WITH ORDINALITY AS t(val, idx) WHERE t.val > $2", sql)
+	assert.Equal(t, []interface{}{"{10,20,30}", 15}, args)
+
+	sql, args, err = bsql.Select("u.id").
+		From("users u").
+		JoinClause(bsql.ConcatExpr("JOIN ", Unnest([]string{"a", "b"}, "text[]", "n(name)", false), " ON n.name = u.name")).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT u.id FROM users u JOIN unnest(?::text[]) AS n(name) ON n.name = u.name", sql)
+	assert.Equal(t, []interface{}{`{"a","b"}`}, args)
+
+	_, _, err = Unnest([]bool{true}, "bool[]", "b", false).ToSql()
+	assert.Error(t, err)
+}
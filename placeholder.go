@@ -3,6 +3,7 @@ package bsql
 import (
 	"bytes"
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -22,23 +23,109 @@ var (
 	// Dollar is a PlaceholderFormat instance that replaces placeholders with
 	// dollar-prefixed positional placeholders (e.g. $1, $2, $3).
 	Dollar = dollarFormat{}
+
+	// AtP is a PlaceholderFormat instance that replaces placeholders with
+	// "@p"-prefixed positional placeholders (e.g. @p1, @p2, @p3), as used by
+	// SQL Server.
+	AtP = atpFormat{}
+
+	// Colon is a PlaceholderFormat instance that replaces placeholders with
+	// colon-prefixed positional placeholders (e.g. :1, :2, :3), as used by
+	// Oracle.
+	Colon = colonFormat{}
 )
 
+// offsetFormat is implemented by the positional formats, which can number
+// placeholders from offset+1 instead of 1. See SelectBuilder.PlaceholderOffset.
+type offsetFormat interface {
+	withOffset(offset int) PlaceholderFormat
+}
+
 type questionFormat struct{}
 
 func (_ questionFormat) ReplacePlaceholders(sql string) (string, error) {
+	if maxArgRef(sql) > 0 {
+		return "", buildErrorf(CodeInvalidClause, "ArgRef requires a positional placeholder format like Dollar, not Question")
+	}
 	return sql, nil
 }
 
-type dollarFormat struct{}
+type dollarFormat struct {
+	offset int
+}
+
+func (f dollarFormat) ReplacePlaceholders(sql string) (string, error) {
+	return replacePlaceholders(sql, func(buf *bytes.Buffer, i int) error {
+		fmt.Fprintf(buf, "$%d", f.offset+i)
+		return nil
+	})
+}
+
+func (f dollarFormat) withOffset(offset int) PlaceholderFormat {
+	f.offset = offset
+	return f
+}
+
+type atpFormat struct {
+	offset int
+}
+
+func (f atpFormat) ReplacePlaceholders(sql string) (string, error) {
+	return replacePlaceholders(sql, func(buf *bytes.Buffer, i int) error {
+		fmt.Fprintf(buf, "@p%d", f.offset+i)
+		return nil
+	})
+}
+
+func (f atpFormat) withOffset(offset int) PlaceholderFormat {
+	f.offset = offset
+	return f
+}
+
+type colonFormat struct {
+	offset int
+}
 
-func (_ dollarFormat) ReplacePlaceholders(sql string) (string, error) {
+func (f colonFormat) ReplacePlaceholders(sql string) (string, error) {
 	return replacePlaceholders(sql, func(buf *bytes.Buffer, i int) error {
-		fmt.Fprintf(buf, "$%d", i)
+		fmt.Fprintf(buf, ":%d", f.offset+i)
 		return nil
 	})
 }
 
+func (f colonFormat) withOffset(offset int) PlaceholderFormat {
+	f.offset = offset
+	return f
+}
+
+// Format applies f to sql built with question mark placeholders, for when
+// the target database is only known after the query was built. A nil f is
+// the same as Question. args are returned unchanged.
+//
+//   sql, args, err := Select("*").From("t").Where(Eq{"id": 1}).ToSql()
+//   sql, args, err = Format(sql, args, Dollar)
+func Format(sql string, args []interface{}, f PlaceholderFormat) (string, []interface{}, error) {
+	if f == nil {
+		f = Question
+	}
+	sql, err := f.ReplacePlaceholders(sql)
+	if err != nil {
+		return "", nil, err
+	}
+	return sql, args, nil
+}
+
+// Rebind renders the question mark placeholders of sql, as built with the
+// Question format, in format. The ?? escape is handled as by the builders'
+// ToSql. A nil format is the same as Question.
+//
+// Build once with Question and Rebind per backend; the original sql can be
+// rebound any number of times.
+func Rebind(format PlaceholderFormat, sql string) (string, error) {
+	sql, _, err := Format(sql, nil, format)
+	return sql, err
+}
+
 // Placeholders returns a string with count ? placeholders joined with commas.
 func Placeholders(count int) string {
 	if count < 1 {
@@ -49,6 +136,116 @@ func Placeholders(count int) string {
 }
 
 func replacePlaceholders(sql string, replace func(buf *bytes.Buffer, i int) error) (string, error) {
+	return rewritePlaceholders(sql, false, replace)
+}
+
+type argRef int
+
+// ArgRef refers to the n-th arg of the query, counting from 1, in place of
+// binding a new arg, so that one arg can be used by several placeholders.
+// It can be used as an Expr arg or a value, and is rendered by the
+// positional formats as the placeholder of that arg:
+//
+//   Select("*").From("t").Where("a = ? OR b = ?", 7, ArgRef(1)).PlaceholderFormat(Dollar)
+//   // SELECT * FROM t WHERE a = $1 OR b = $1, with args [7]
+//
+// Question placeholders cannot refer to an arg, so ToSql fails with Question.
+func ArgRef(n int) Sqlizer {
+	return argRef(n)
+}
+
+// argRefMark delimits the rendering of an ArgRef, "?\x00n\x00". SQL text
+// cannot contain NUL bytes, so the rendering cannot clash with a ? of user
+// SQL, such as the regex quantifier in "x ~ '^a?{2}$'".
+const argRefMark = "\x00"
+
+// ToSql renders the reference as "?\x00n\x00", which the placeholder
+// formats replace.
+func (r argRef) ToSql() (string, []interface{}, error) {
+	if r < 1 {
+		return "", nil, buildErrorf(CodeArgMismatch, "ArgRef must be at least 1, got %d", int(r))
+	}
+	return "?" + argRefMark + strconv.Itoa(int(r)) + argRefMark, nil, nil
+}
+
+// parseArgRef parses the rendering of ArgRef(n) at the start of sql,
+// returning n and the length of the reference.
+func parseArgRef(sql string) (n int, size int, ok bool) {
+	if !strings.HasPrefix(sql, "?"+argRefMark) {
+		return 0, 0, false
+	}
+	end := strings.Index(sql[2:], argRefMark) + 2
+	if end < 3 {
+		return 0, 0, false
+	}
+	n, err := strconv.Atoi(sql[2:end])
+	if err != nil || n < 1 {
+		return 0, 0, false
+	}
+	return n, end + 1, true
+}
+
+// maxArgRef returns the largest arg referenced by an ArgRef in sql, or 0.
+func maxArgRef(sql string) int {
+	max := 0
+	for i := 0; i < len(sql); i++ {
+		if sql[i] != '?' {
+			continue
+		}
+		if i+1 < len(sql) && sql[i+1] == '?' {
+			i++
+			continue
+		}
+		if n, size, ok := parseArgRef(sql[i:]); ok {
+			if n > max {
+				max = n
+			}
+			i += size - 1
+		}
+	}
+	return max
+}
+
+// countPlaceholders returns the number of ? placeholders in sql, not counting
+// ?? escapes and ArgRefs.
+func countPlaceholders(sql string) int {
+	n := 0
+	for i := 0; i < len(sql); i++ {
+		if sql[i] != '?' {
+			continue
+		}
+		if i+1 < len(sql) && sql[i+1] == '?' {
+			i++
+			continue
+		}
+		if _, size, ok := parseArgRef(sql[i:]); ok {
+			i += size - 1
+			continue
+		}
+		n++
+	}
+	return n
+}
+
+// rewritePlaceholders calls replace for each ? placeholder in sql. The ??
+// escape is unescaped to ? unless keepEscapes is set, which is needed when
+// the result is rewritten again by the enclosing statement. Likewise, an
+// ArgRef(n) is kept unless keepEscapes is unset, in which case replace is
+// called with n without counting it as a placeholder.
+func rewritePlaceholders(sql string, keepEscapes bool, replace func(buf *bytes.Buffer, i int) error) (string, error) {
+	return rewritePlaceholdersRest(sql, keepEscapes, func(buf *bytes.Buffer, i int, _ string) error {
+		return replace(buf, i)
+	})
+}
+
+// rewritePlaceholdersRest is rewritePlaceholders, but also passes replace the
+// SQL that follows the placeholder.
+func rewritePlaceholdersRest(sql string, keepEscapes bool, replace func(buf *bytes.Buffer, i int, rest string) error) (string, error) {
+	escape := "?"
+	if keepEscapes {
+		escape = "??"
+	}
+
 	buf := &bytes.Buffer{}
 	i := 0
 	for {
@@ -57,21 +254,28 @@ func replacePlaceholders(sql string, replace func(buf *bytes.Buffer, i int) erro
 			break
 		}
 
-		if len(sql[p:]) > 1 && sql[p:p+2] == "??" { // escape ?? => ?
-			buf.WriteString(sql[:p])
-			buf.WriteString("?")
-			if len(sql[p:]) == 1 {
-				break
-			}
+		buf.WriteString(sql[:p])
+		if strings.HasPrefix(sql[p:], "??") { // escape ?? => ?
+			buf.WriteString(escape)
 			sql = sql[p+2:]
-		} else {
-			i++
-			buf.WriteString(sql[:p])
-			if err := replace(buf, i); err != nil {
+			continue
+		}
+
+		if n, size, ok := parseArgRef(sql[p:]); ok {
+			if keepEscapes {
+				buf.WriteString(sql[p : p+size])
+			} else if err := replace(buf, n, sql[p+size:]); err != nil {
 				return "", err
 			}
-			sql = sql[p+1:]
+			sql = sql[p+size:]
+			continue
+		}
+
+		i++
+		if err := replace(buf, i, sql[p+1:]); err != nil {
+			return "", err
 		}
+		sql = sql[p+1:]
 	}
 
 	buf.WriteString(sql)
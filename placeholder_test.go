@@ -0,0 +1,170 @@
+package bsql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatDeferred(t *testing.T) {
+	b := NewSelectBuilder(StatementBuilderType{}).
+		Columns("id").
+		From("users").
+		Where(Eq{"team": 1}).
+		Where("name LIKE ?", "a%")
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE team = ? AND name LIKE ?", sql)
+
+	qSql, qArgs, err := Format(sql, args, Question)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE team = ? AND name LIKE ?", qSql)
+	assert.Equal(t, args, qArgs)
+
+	dSql, dArgs, err := Format(sql, args, Dollar)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE team = $1 AND name LIKE $2", dSql)
+	assert.Equal(t, []interface{}{1, "a%"}, dArgs)
+
+	nSql, _, err := Format(sql, args, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, nSql)
+}
+
+func TestDollarEscapes(t *testing.T) {
+	tests := []struct {
+		sql, expected string
+	}{
+		{"a = ? AND b = ?", "a = $1 AND b = $2"},
+		{"SELECT a ??", "SELECT a ?"},
+		{"SELECT a ?? FROM t WHERE b = ?", "SELECT a ? FROM t WHERE b = $1"},
+		{"data ??| array['a'] AND x = ?", "data ?| array['a'] AND x = $1"},
+		{"data ??& array['a'] AND x = ? ??", "data ?& array['a'] AND x = $1 ?"},
+		{"????", "??"},
+		{"???", "?$1"},
+		{"?", "$1"},
+		{"no placeholders", "no placeholders"},
+	}
+	for _, test := range tests {
+		sql, err := Dollar.ReplacePlaceholders(test.sql)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, sql, test.sql)
+
+		sql, err = Question.ReplacePlaceholders(test.sql)
+		assert.NoError(t, err)
+		assert.Equal(t, test.sql, sql)
+	}
+}
+
+func TestEscapesSurviveNesting(t *testing.T) {
+	sub := Select("id").From("nodes").Where("data ??& array['a'] AND kind = ?", 1)
+	b := Select("*").From("t").Where(Eq{"node_id": sub}).Where("tags ??| ?", "x").
+		PlaceholderFormat(Dollar)
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT * FROM t WHERE node_id IN (SELECT id FROM nodes WHERE data ?& array['a'] AND kind = $1) " +
+		"AND tags ?| $2"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{1, "x"}, args)
+}
+
+func TestRebind(t *testing.T) {
+	sql, _, err := Select("id").From("nodes").
+		Where(Eq{"kind": "a"}).
+		Where("tags ??| array['x'] AND created > ?", 10).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM nodes WHERE kind = ? AND tags ??| array['x'] AND created > ?", sql)
+
+	dollar, err := Rebind(Dollar, sql)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM nodes WHERE kind = $1 AND tags ?| array['x'] AND created > $2", dollar)
+
+	question, err := Rebind(Question, sql)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, question)
+
+	again, err := Rebind(Dollar, question)
+	assert.NoError(t, err)
+	assert.Equal(t, dollar, again)
+}
+
+func TestPlaceholderOffset(t *testing.T) {
+	b := Select("id").From("t").Where(Eq{"a": 1}).Where("b > ?", 2).PlaceholderOffset(3)
+
+	sql, args, err := b.PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t WHERE a = $4 AND b > $5", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+
+	sql, _, err = b.PlaceholderFormat(AtP).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t WHERE a = @p4 AND b > @p5", sql)
+
+	sql, _, err = b.PlaceholderFormat(Colon).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t WHERE a = :4 AND b > :5", sql)
+
+	sql, _, err = b.PlaceholderFormat(Question).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t WHERE a = ? AND b > ?", sql)
+
+	sql, _, err = StatementBuilder.PlaceholderFormat(Dollar).PlaceholderOffset(1).
+		Update("t").Set("a", 1).Where(Eq{"id": 2}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = $2 WHERE id = $3", sql)
+}
+
+func TestArgRef(t *testing.T) {
+	b := Select("*").
+		From("tasks").
+		Where("owner_id = ? OR assignee_id = ?", 7, ArgRef(1)).
+		Where(Eq{"state": "open"}).
+		Where(Expr("reviewer_id = ?", ArgRef(1)))
+
+	sql, args, err := b.PlaceholderFormat(Dollar).StrictArgs(true).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM tasks WHERE owner_id = $1 OR assignee_id = $1 AND state = $2 AND reviewer_id = $1", sql)
+	assert.Equal(t, []interface{}{7, "open"}, args)
+
+	sql, _, err = Update("t").Set("a", 1).Set("b", ArgRef(1)).PlaceholderFormat(AtP).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = @p1, b = @p1", sql)
+
+	assert.Equal(t, "SELECT * FROM tasks WHERE owner_id = 7 OR assignee_id = 7 AND state = 'open' AND reviewer_id = 7", DebugSqlizer(b))
+
+	_, _, err = b.PlaceholderFormat(Question).ToSql()
+	assert.EqualError(t, err, "ArgRef requires a positional placeholder format like Dollar, not Question")
+
+	_, _, err = Select("*").From("t").Where("a = ?", ArgRef(2)).PlaceholderFormat(Dollar).ToSql()
+	assert.ErrorIs(t, err, ErrArgMismatch)
+
+	_, _, err = NewWhereBuilder(StatementBuilder.PlaceholderFormat(Dollar)).Where("a = ?", ArgRef(3)).ToSql()
+	assert.ErrorIs(t, err, ErrArgMismatch)
+	_, _, err = NewWhereBuilder(StatementBuilder.PlaceholderFormat(Dollar)).Where("a = ?", ArgRef(3)).WhereToSql()
+	assert.ErrorIs(t, err, ErrArgMismatch)
+}
+
+func TestArgRefEq(t *testing.T) {
+	sql, args, err := Select("*").From("t").
+		Where(Eq{"a": 5}).
+		Where(Eq{"b": ArgRef(1)}).
+		Where(NotEq{"c": ArgRef(1)}).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE a = $1 AND b = $1 AND c <> $1", sql)
+	assert.Equal(t, []interface{}{5}, args)
+}
+
+func TestArgRefLookalike(t *testing.T) {
+	sql, _, err := Select("*").From("t").Where("x ~ '^a?{2}$'").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE x ~ '^a?{2}$'", sql)
+
+	b := Select("*").From("t").Where("x ~ '^a??{2}$' AND y = ?", 1)
+	sql, _, err = b.PlaceholderFormat(Dollar).StrictArgs(true).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE x ~ '^a?{2}$' AND y = $1", sql)
+}
@@ -0,0 +1,149 @@
+package bsql
+
+import "strings"
+
+// QuoteStyle describes how identifiers such as table and column names are
+// quoted. The zero value leaves identifiers unquoted.
+type QuoteStyle struct {
+	open, close string
+}
+
+var (
+	// ANSIQuotes quotes identifiers with double quotes, e.g. "t"."col", as
+	// used by PostgreSQL and SQLite.
+	ANSIQuotes = QuoteStyle{`"`, `"`}
+
+	// MySQLQuotes quotes identifiers with backticks, e.g. `t`.`col`.
+	MySQLQuotes = QuoteStyle{"`", "`"}
+
+	// BracketQuotes quotes identifiers with square brackets, e.g. [t].[col],
+	// as used by SQL Server.
+	BracketQuotes = QuoteStyle{"[", "]"}
+)
+
+// Quote quotes name, quoting each part of a dotted name separately and
+// doubling any embedded closing quote character. A "*" part is left as is.
+func (q QuoteStyle) Quote(name string) string {
+	if q == (QuoteStyle{}) {
+		return name
+	}
+
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		if part == "*" {
+			continue
+		}
+		parts[i] = q.open + strings.ReplaceAll(part, q.close, q.close+q.close) + q.close
+	}
+	return strings.Join(parts, ".")
+}
+
+// QuoteIdentifier quotes name with ANSI double quotes, e.g. t.col becomes
+// "t"."col".
+func QuoteIdentifier(name string) string {
+	return ANSIQuotes.Quote(name)
+}
+
+// orderKeywords may follow the expression of an ORDER BY item.
+var orderKeywords = map[string]bool{
+	"ASC": true, "DESC": true, "NULLS": true, "FIRST": true, "LAST": true,
+}
+
+// identifier is a table or column name given as a string, quoted when the
+// statement has a QuoteStyle and the name is a plain identifier. It renders
+// unquoted otherwise.
+type identifier struct {
+	name string
+	// order allows trailing ASC/DESC/NULLS FIRST/LAST keywords.
+	order bool
+	// alias is rendered as "name AS alias" if set.
+	alias string
+}
+
+func (id identifier) ToSql() (string, []interface{}, error) {
+	if id.alias != "" {
+		return id.name + " AS " + id.alias, nil, nil
+	}
+	return id.name, nil, nil
+}
+
+// quote returns id with its name quoted by q if it is a plain identifier,
+// optionally followed by ORDER BY keywords or "AS alias". Any other name,
+// such as "COUNT(*)", is an expression and is left as is.
+func (id identifier) quote(q QuoteStyle) identifier {
+	if q == (QuoteStyle{}) {
+		return id
+	}
+
+	fields := strings.Fields(id.name)
+	n, alias := len(fields), id.alias
+	if id.order {
+		for n > 1 && orderKeywords[strings.ToUpper(fields[n-1])] {
+			n--
+		}
+	} else if alias == "" && n == 3 && strings.EqualFold(fields[1], "AS") && isIdentifierPart(fields[2]) {
+		n, alias = 1, fields[2]
+	}
+	if n != 1 || !isPlainName(fields[0]) {
+		return id
+	}
+
+	quoted := q.Quote(fields[0])
+	if suffix := strings.Join(fields[1:], " "); id.order && suffix != "" {
+		quoted += " " + suffix
+	}
+	if isIdentifierPart(alias) {
+		alias = q.Quote(alias)
+	}
+	return identifier{name: quoted, alias: alias}
+}
+
+// quoteName quotes name with q if it is a plain, optionally dotted
+// identifier and returns it as is otherwise.
+func (q QuoteStyle) quoteName(name string) string {
+	if q == (QuoteStyle{}) || !isPlainName(name) {
+		return name
+	}
+	return q.Quote(name)
+}
+
+// isPlainName reports whether name is a dotted list of identifiers, of
+// which the last may be *.
+func isPlainName(name string) bool {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		if !isIdentifierPart(part) && !(part == "*" && i == len(parts)-1) {
+			return false
+		}
+	}
+	return true
+}
+
+// isIdentifierPart reports whether s matches [A-Za-z_][A-Za-z0-9_$]*.
+func isIdentifierPart(s string) bool {
+	for i, r := range s {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && (r == '$' || r >= '0' && r <= '9'):
+		default:
+			return false
+		}
+	}
+	return s != ""
+}
+
+// quoteIdentifiers returns parts with identifiers quoted by q.
+func quoteIdentifiers(parts []Sqlizer, q QuoteStyle) []Sqlizer {
+	if q == (QuoteStyle{}) {
+		return parts
+	}
+
+	quoted := make([]Sqlizer, len(parts))
+	for i, p := range parts {
+		if id, ok := p.(identifier); ok {
+			p = id.quote(q)
+		}
+		quoted[i] = p
+	}
+	return quoted
+}
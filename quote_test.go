@@ -0,0 +1,68 @@
+package bsql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteIdentifier(t *testing.T) {
+	assert.Equal(t, `"col"`, QuoteIdentifier("col"))
+	assert.Equal(t, `"t"."col"`, QuoteIdentifier("t.col"))
+	assert.Equal(t, `"t".*`, QuoteIdentifier("t.*"))
+	assert.Equal(t, `"a""; DROP TABLE users; --"`, QuoteIdentifier(`a"; DROP TABLE users; --`))
+	assert.Equal(t, "`a``b`.`c`", MySQLQuotes.Quote("a`b.c"))
+	assert.Equal(t, "t.col", QuoteStyle{}.Quote("t.col"))
+}
+
+func TestSelectBuilderQuoting(t *testing.T) {
+	b := Select("u.id", "name AS n", "t.*", "COUNT(*)", "a + b AS total", "1").
+		From("users u").
+		GroupBy("u.id", "lower(name)").
+		OrderBy("u.id DESC", "name nulls last", "length(name) ASC").
+		Quoting(ANSIQuotes)
+	sql, _, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := `SELECT "u"."id", "name" AS "n", "t".*, COUNT(*), a + b AS total, 1 FROM users u ` +
+		`GROUP BY "u"."id", lower(name) ` +
+		`ORDER BY "u"."id" DESC, "name" nulls last, length(name) ASC`
+	assert.Equal(t, expectedSql, sql)
+}
+
+func TestSelectBuilderQuotingIntoDistinctOn(t *testing.T) {
+	sql, _, err := Select("id", "col$1").
+		DistinctOn("team_id", "lower(name)").
+		Into("app.archive").
+		From("users").
+		Quoting(ANSIQuotes).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT DISTINCT ON ("team_id", lower(name)) "id", "col$1" INTO "app"."archive" FROM users`, sql)
+}
+
+func TestInsertBuilderQuoting(t *testing.T) {
+	sb := StatementBuilder.Quoting(MySQLQuotes)
+	sql, args, err := sb.Insert("app.users").Columns("id", "select").Values(1, "x").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `app`.`users` (`id`,`select`) VALUES (?,?)", sql)
+	assert.Equal(t, []interface{}{1, "x"}, args)
+}
+
+func TestBracketQuotes(t *testing.T) {
+	assert.Equal(t, "[a]]b]", BracketQuotes.Quote("a]b"))
+	assert.Equal(t, "[dbo].[users]", BracketQuotes.Quote("dbo.users"))
+
+	sql, _, err := Select("name AS n", "u.*").
+		FromAs("dbo.users", "u").
+		OrderBy("name DESC").
+		Quoting(BracketQuotes).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT [name] AS [n], [u].* FROM [dbo].[users] AS [u] ORDER BY [name] DESC", sql)
+
+	sql, _, err = StatementBuilder.Quoting(BracketQuotes).
+		Insert("dbo.users").Columns("id", "a]b").Values(1, 2).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO [dbo].[users] ([id],[a]]b]) VALUES (?,?)", sql)
+}
@@ -1,10 +1,25 @@
 package bsql
 
-import "io"
+import (
+	"context"
+	"io"
+)
+
+// ErrNoReturning is returned by ExecReturning when the statement has no
+// RETURNING clause.
+var ErrNoReturning = &BuildError{Code: CodeNoReturning, Msg: "cannot scan returned values; no RETURNING clause set"}
 
 type returning []Sqlizer
 
+// allColumns renders the * of RETURNING *.
+type allColumns struct{}
+
+func (allColumns) ToSql() (string, []interface{}, error) {
+	return "*", nil, nil
+}
+
 func (r *returning) Returning(columns ...string) {
+	r.dropAll()
 	parts := make([]Sqlizer, len(columns))
 	for i, column := range columns {
 		parts[i] = newPart(column)
@@ -13,11 +28,32 @@ func (r *returning) Returning(columns ...string) {
 }
 
 func (r *returning) ReturningSelect(from *SelectBuilder, alias string) {
+	r.dropAll()
 	*r = append(*r, Alias(from, alias))
 }
 
+// ReturningAll replaces the RETURNING clause with RETURNING *.
+func (r *returning) ReturningAll() {
+	*r = returning{allColumns{}}
+}
+
+// dropAll clears a RETURNING * set by ReturningAll, so that the columns
+// added after it replace it.
+func (r *returning) dropAll() {
+	if len(*r) == 1 && (*r)[0] == (allColumns{}) {
+		*r = nil
+	}
+}
+
 func (r *returning) AppendToSql(w io.Writer, args []interface{}) ([]interface{}, error) {
-	io.WriteString(w, " RETURNING ")
-	return appendToSql(*r, w, ", ", args)
+	return appendClauseToSql(*r, w, " RETURNING ", ", ", args)
+}
 
+// execReturning runs s with runner and scans the single row it returns into
+// dest.
+func (r returning) execReturning(ctx context.Context, runner BaseRunner, s Sqlizer, dest ...interface{}) error {
+	if len(r) == 0 {
+		return ErrNoReturning
+	}
+	return queryRowContext(ctx, runner, s).Scan(dest...)
 }
@@ -2,7 +2,12 @@ package bsql
 
 import (
 	"bytes"
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"strconv"
 	"strings"
 )
@@ -11,23 +16,40 @@ import (
 type SelectBuilder struct {
 	StatementBuilderType
 
+	comment     string
 	prefixes    exprs
+	ctes        []cte
 	distinct    bool
+	distinctOn  []string
 	options     []string
 	columns     []Sqlizer
+	into        string
+	intoTemp    bool
 	fromParts   []Sqlizer
 	joins       []Sqlizer
+	prewhere    []Sqlizer
 	whereParts  []Sqlizer
-	groupBys    []string
+	groupBys    []Sqlizer
 	havingParts []Sqlizer
-	orderBys    []string
+	windows     []namedWindow
+	unions      []setOperation
+	orderBys    []Sqlizer
+
+	orderByPositions []int
 
 	limit       uint64
 	limitValid  bool
 	offset      uint64
 	offsetValid bool
 
+	settings setClauses
+	lock     rowLock
+
 	suffixes exprs
+
+	limits clauseLimits
+
+	err error
 }
 
 // NewSelectBuilder creates new instance of SelectBuilder
@@ -35,6 +57,63 @@ func NewSelectBuilder(b StatementBuilderType) *SelectBuilder {
 	return &SelectBuilder{StatementBuilderType: b}
 }
 
+// Clone returns a copy of b that can be changed without affecting b, e.g. to
+// branch several queries off a common base:
+//
+//   base := Select("*").From("users").Where(Eq{"team": 1})
+//   active := base.Clone().Where(Eq{"active": true})
+//   admins := base.Clone().Where(Eq{"role": "admin"})
+//
+// Slices are capped so that appending to either copy reallocates them.
+// Subqueries and other Sqlizers added to b are shared by the copy.
+func (b *SelectBuilder) Clone() *SelectBuilder {
+	c := *b
+	c.prefixes = c.prefixes[:len(c.prefixes):len(c.prefixes)]
+	c.ctes = c.ctes[:len(c.ctes):len(c.ctes)]
+	c.distinctOn = c.distinctOn[:len(c.distinctOn):len(c.distinctOn)]
+	c.options = c.options[:len(c.options):len(c.options)]
+	c.columns = c.columns[:len(c.columns):len(c.columns)]
+	c.fromParts = c.fromParts[:len(c.fromParts):len(c.fromParts)]
+	c.joins = c.joins[:len(c.joins):len(c.joins)]
+	c.prewhere = c.prewhere[:len(c.prewhere):len(c.prewhere)]
+	c.whereParts = c.whereParts[:len(c.whereParts):len(c.whereParts)]
+	c.groupBys = c.groupBys[:len(c.groupBys):len(c.groupBys)]
+	c.havingParts = c.havingParts[:len(c.havingParts):len(c.havingParts)]
+	c.windows = c.windows[:len(c.windows):len(c.windows)]
+	c.unions = c.unions[:len(c.unions):len(c.unions)]
+	c.orderBys = c.orderBys[:len(c.orderBys):len(c.orderBys)]
+	c.orderByPositions = c.orderByPositions[:len(c.orderByPositions):len(c.orderByPositions)]
+	c.settings = c.settings[:len(c.settings):len(c.settings)]
+	c.lock.of = c.lock.of[:len(c.lock.of):len(c.lock.of)]
+	c.suffixes = c.suffixes[:len(c.suffixes):len(c.suffixes)]
+	return &c
+}
+
+// ForTable returns a copy of the query reading from table instead of the
+// first table of its FROM clause, e.g. to run the same query against several
+// shards. Joins, subqueries and all other clauses are kept as they are.
+//
+// A first table added with FromAs keeps its alias and is quoted as before;
+// one added with From is replaced as a whole, so table should repeat its
+// alias, e.g. ForTable("events_1 e").
+func (b *SelectBuilder) ForTable(table string) *SelectBuilder {
+	c := b.Clone()
+	first := newPart(table)
+	if len(c.fromParts) > 0 {
+		if id, ok := c.fromParts[0].(identifier); ok {
+			id.name = table
+			first = id
+		}
+	}
+	fromParts := make([]Sqlizer, 0, len(c.fromParts)+1)
+	fromParts = append(fromParts, first)
+	if len(c.fromParts) > 0 {
+		fromParts = append(fromParts, c.fromParts[1:]...)
+	}
+	c.fromParts = fromParts
+	return c
+}
+
 // PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
 // query.
 func (b *SelectBuilder) PlaceholderFormat(f PlaceholderFormat) *SelectBuilder {
@@ -42,24 +121,266 @@ func (b *SelectBuilder) PlaceholderFormat(f PlaceholderFormat) *SelectBuilder {
 	return b
 }
 
+// TagCaller makes ToSql append a "/* at file.go:123 */" comment naming the
+// code outside of bsql that built the query, to trace queries seen in
+// database logs back to their source. ToSql fails if no such caller is found.
+func (b *SelectBuilder) TagCaller(tag bool) *SelectBuilder {
+	b.tagCaller = tag
+	return b
+}
+
+// Quoting sets the QuoteStyle used for the identifiers given to Columns,
+// FromAs, GroupBy, OrderBy, DistinctOn and Into. Only plain, possibly dotted,
+// names are quoted, with a trailing "AS alias" in Columns and ASC, DESC and
+// NULLS FIRST/LAST keywords in OrderBy; expressions such as "COUNT(*)" are
+// left as is.
+func (b *SelectBuilder) Quoting(style QuoteStyle) *SelectBuilder {
+	b.quoting = style
+	return b
+}
+
+// PlaceholderOffset makes the positional placeholder formats (Dollar, AtP
+// and Colon) number the placeholders of the query from n+1, so that it can
+// be spliced into a hand-written query that already uses $1..$n. It has no
+// effect on Question.
+func (b *SelectBuilder) PlaceholderOffset(n int) *SelectBuilder {
+	b.placeholderOffset = n
+	return b
+}
+
+// StrictArgs makes ToSql check that the number of placeholders in the query
+// matches the number of args, so that e.g. Expr("a = ? AND b = ?", 1) fails
+// to build instead of failing in the database driver. ?? escapes are not
+// counted.
+func (b *SelectBuilder) StrictArgs(strict bool) *SelectBuilder {
+	b.strictArgs = strict
+	return b
+}
+
+// PreEvalValuers makes ToSql call Value on the driver.Valuer args of the
+// query and return their values as args instead, so that a Valuer failing to
+// convert is reported when the query is built rather than when it is run.
+func (b *SelectBuilder) PreEvalValuers(eval bool) *SelectBuilder {
+	b.preEvalValuers = eval
+	return b
+}
+
+// NormalizeNils makes ToSql replace nil pointer args, like a nil *string,
+// by an untyped nil, which every driver binds as NULL. driver.Valuer args are
+// left alone.
+func (b *SelectBuilder) NormalizeNils(normalize bool) *SelectBuilder {
+	b.normalizeNils = normalize
+	return b
+}
+
+// BoolAsInt makes ToSql bind bool args as 1 and 0, for MySQL and SQLite
+// drivers that do not handle bool args consistently. PostgreSQL has a native
+// boolean type and needs no conversion.
+func (b *SelectBuilder) BoolAsInt(asInt bool) *SelectBuilder {
+	b.boolAsInt = asInt
+	return b
+}
+
+// StandardLimitSyntax makes ToSql render Limit and Offset in the SQL
+// standard form, "OFFSET n ROWS FETCH NEXT m ROWS ONLY", as used by DB2 and
+// Oracle, instead of "LIMIT m OFFSET n". SQL Server queries always use it.
+func (b *SelectBuilder) StandardLimitSyntax(standard bool) *SelectBuilder {
+	b.standardLimit = standard
+	return b
+}
+
+// PreferAnyArray makes Eq and NotEq (and map predicates) in Where and Having
+// compare a slice value to a single array arg, as "col = ANY(?)" and
+// "col <> ALL(?)", instead of expanding it to one placeholder per element.
+// The slice is bound as is, so the driver must accept it as a Postgres array
+// (pgx does; with lib/pq use pg.Eq instead). It requires the Postgres
+// dialect.
+//
+// Ex:
+//     StatementBuilder.Dialect(Postgres).PreferAnyArray(true).
+//         Select("name").From("users").Where(Eq{"id": ids})
+//     // SELECT "name" FROM users WHERE id = ANY($1)
+func (b *SelectBuilder) PreferAnyArray(prefer bool) *SelectBuilder {
+	b.preferAnyArray = prefer
+	return b
+}
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+//
+// *sql.DB and *sql.Tx are wrapped automatically.
+func (b *SelectBuilder) RunWith(runner BaseRunner) *SelectBuilder {
+	b.runner = wrapRunner(runner)
+	return b
+}
+
+// Exec builds and executes the query with the Runner set by RunWith.
+func (b *SelectBuilder) Exec() (sql.Result, error) {
+	return exec(b.runner, b)
+}
+
+// Query builds and executes the query with the Runner set by RunWith.
+func (b *SelectBuilder) Query() (*sql.Rows, error) {
+	return query(b.runner, b)
+}
+
+// QueryRow builds and executes the query with the Runner set by RunWith.
+// The Runner must implement QueryRower.
+func (b *SelectBuilder) QueryRow() RowScanner {
+	return queryRow(b.runner, b)
+}
+
+// ExecContext builds and executes the query with the Runner set by RunWith.
+// The Runner must implement ExecerContext.
+func (b *SelectBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
+	return execContext(ctx, b.runner, b)
+}
+
+// QueryContext builds and executes the query with the Runner set by RunWith.
+// The Runner must implement QueryerContext.
+func (b *SelectBuilder) QueryContext(ctx context.Context) (*sql.Rows, error) {
+	return queryContext(ctx, b.runner, b)
+}
+
+// QueryRowContext builds and executes the query with the Runner set by
+// RunWith. The Runner must implement QueryRowerContext.
+func (b *SelectBuilder) QueryRowContext(ctx context.Context) RowScanner {
+	return queryRowContext(ctx, b.runner, b)
+}
+
+// ScanContext is a shortcut for QueryRowContext().Scan(dest...). Errors,
+// including sql.ErrNoRows, are returned unchanged.
+func (b *SelectBuilder) ScanContext(ctx context.Context, dest ...interface{}) error {
+	return b.QueryRowContext(ctx).Scan(dest...)
+}
+
+// QueryStructs runs the query with QueryContext and appends each returned row
+// to the slice dest points to, which must be a []T or []*T for a struct type
+// T. Columns are matched to fields by their `db` tag as by
+// InsertBuilder.SetStruct; a column without a matching field is an error.
+//
+//   var users []User
+//   err := Select("id", "name").From("users").RunWith(db).QueryStructs(ctx, &users)
+func (b *SelectBuilder) QueryStructs(ctx context.Context, dest interface{}) error {
+	if _, err := structSliceType(dest); err != nil {
+		return err
+	}
+	rows, err := b.QueryContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	return scanStructs(rows, dest)
+}
+
+// ToCountSql builds a query counting the rows the query returns, ignoring its
+// ORDER BY, LIMIT, OFFSET and row locking clauses, e.g. to report the total
+// of a paginated listing. A plain query has its result columns replaced:
+//
+//   SELECT COUNT(*) FROM users WHERE team = ?
+//
+// Queries with GROUP BY, HAVING, DISTINCT, set operations or a Suffix, which
+// may limit the rows, are counted in a subquery instead:
+//
+//   SELECT COUNT(*) FROM (SELECT team FROM users GROUP BY team) AS _count
+func (b *SelectBuilder) ToCountSql() (string, []interface{}, error) {
+	c := b.Clone().ClearLimit().ClearOffset()
+	c.orderBys = nil
+	c.orderByPositions = nil
+	c.lock = rowLock{}
+
+	if len(c.groupBys) == 0 && len(c.havingParts) == 0 && !c.distinct &&
+		len(c.distinctOn) == 0 && len(c.unions) == 0 && len(c.suffixes) == 0 {
+		c.columns = []Sqlizer{newPart("COUNT(*)")}
+		c.windows = nil
+		return c.ToSql()
+	}
+
+	return NewSelectBuilder(b.StatementBuilderType).
+		Column("COUNT(*)").
+		FromSelect(c, "_count").
+		ToSql()
+}
+
+// Scan is a shortcut for QueryRow().Scan(dest...).
+func (b *SelectBuilder) Scan(dest ...interface{}) error {
+	return b.QueryRow().Scan(dest...)
+}
+
 // ToSql builds the query into a SQL string and bound args.
 func (b *SelectBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
+	sqlStr, args, err = b.toSqlRaw()
+	if err != nil {
+		return
+	}
+
+	sqlStr, args, err = b.finalize(sqlStr, args)
+	return
+}
+
+// validate reports all structural problems of the query at once.
+func (b *SelectBuilder) validate() error {
+	var errs []error
+	if b.err != nil {
+		errs = append(errs, b.err)
+	}
 	if len(b.columns) == 0 {
-		err = fmt.Errorf("select statements must have at least one result column")
+		errs = append(errs, buildErrorf(CodeNoColumns, "select statements must have at least one result column"))
+	}
+	if err := b.limits.check(b.whereParts, len(b.joins), len(b.columns)); err != nil {
+		errs = append(errs, err)
+	}
+	if err := b.checkOrderByPositions(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := checkWindows(b.columns, b.windows); err != nil {
+		errs = append(errs, err)
+	}
+	if err := b.checkPreferAnyArray(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func (b *SelectBuilder) toSqlRaw() (sqlStr string, args []interface{}, err error) {
+	if err = b.validate(); err != nil {
 		return
 	}
 
 	sql := &bytes.Buffer{}
 
 	if len(b.prefixes) > 0 {
-		args, _ = b.prefixes.AppendToSql(sql, " ", args)
+		args, err = b.prefixes.AppendToSql(sql, " ", args)
+		if err != nil {
+			return
+		}
 		sql.WriteString(" ")
 	}
 
+	if len(b.ctes) > 0 {
+		args, err = appendCtesToSql(b.ctes, sql, args)
+		if err != nil {
+			return
+		}
+	}
+
+	if len(b.unions) > 0 {
+		sql.WriteString("(")
+	}
+
 	sql.WriteString("SELECT ")
+	appendComment(sql, b.comment)
 
 	if b.distinct {
 		sql.WriteString("DISTINCT ")
+	} else if len(b.distinctOn) > 0 {
+		sql.WriteString("DISTINCT ON (")
+		for i, column := range b.distinctOn {
+			if i > 0 {
+				sql.WriteString(", ")
+			}
+			sql.WriteString(b.quoting.quoteName(column))
+		}
+		sql.WriteString(") ")
 	}
 
 	if len(b.options) > 0 {
@@ -67,74 +388,158 @@ func (b *SelectBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 		sql.WriteString(" ")
 	}
 
+	if b.useTop() {
+		fmt.Fprintf(sql, "TOP %d ", b.limit)
+	}
+
 	if len(b.columns) > 0 {
-		args, err = appendToSql(b.columns, sql, ", ", args)
+		n := sql.Len()
+		args, err = appendToSql(quoteIdentifiers(b.columns, b.quoting), sql, ", ", args)
 		if err != nil {
 			return
 		}
+		if sql.Len() == n {
+			err = buildErrorf(CodeNoColumns, "select statements must have at least one non-empty result column")
+			return
+		}
+	}
+
+	if b.into != "" {
+		sql.WriteString(" INTO ")
+		if b.intoTemp {
+			sql.WriteString("TEMP ")
+		}
+		sql.WriteString(b.quoting.quoteName(b.into))
 	}
 
 	if len(b.fromParts) > 0 {
-		sql.WriteString(" FROM ")
-		args, err = appendToSql(b.fromParts, sql, ", ", args)
+		args, err = appendClauseToSql(quoteIdentifiers(b.fromParts, b.quoting), sql, " FROM ", ", ", args)
 		if err != nil {
 			return
 		}
 	}
 
 	if len(b.joins) > 0 {
-		sql.WriteString(" ")
-		args, err = appendToSql(b.joins, sql, " ", args)
+		args, err = appendClauseToSql(b.joins, sql, " ", " ", args)
+		if err != nil {
+			return
+		}
+	}
+
+	if len(b.prewhere) > 0 {
+		args, err = appendClauseToSql(b.prewhere, sql, " PREWHERE ", " AND ", args)
 		if err != nil {
 			return
 		}
 	}
 
 	if len(b.whereParts) > 0 {
-		sql.WriteString(" WHERE ")
-		args, err = appendToSql(b.whereParts, sql, " AND ", args)
+		args, err = appendClauseToSql(b.predicates(b.whereParts), sql, " WHERE ", " AND ", args)
 		if err != nil {
 			return
 		}
 	}
 
 	if len(b.groupBys) > 0 {
-		sql.WriteString(" GROUP BY ")
-		sql.WriteString(strings.Join(b.groupBys, ", "))
+		args, err = appendClauseToSql(quoteIdentifiers(b.groupBys, b.quoting), sql, " GROUP BY ", ", ", args)
+		if err != nil {
+			return
+		}
 	}
 
 	if len(b.havingParts) > 0 {
-		sql.WriteString(" HAVING ")
-		args, err = appendToSql(b.havingParts, sql, " AND ", args)
+		args, err = appendClauseToSql(b.predicates(b.havingParts), sql, " HAVING ", " AND ", args)
+		if err != nil {
+			return
+		}
+	}
+
+	if len(b.windows) > 0 {
+		appendWindowsToSql(b.windows, sql)
+	}
+
+	if len(b.unions) > 0 {
+		sql.WriteString(")")
+		args, err = appendSetOperationsToSql(b.unions, sql, args)
 		if err != nil {
 			return
 		}
 	}
 
 	if len(b.orderBys) > 0 {
-		sql.WriteString(" ORDER BY ")
-		sql.WriteString(strings.Join(b.orderBys, ", "))
+		args, err = appendClauseToSql(orderByParts(b.orderBys, b.quoting, b.flavor), sql, " ORDER BY ", ", ", args)
+		if err != nil {
+			return
+		}
 	}
 
-	// TODO: limit == 0 and offswt == 0 are valid. Need to go dbr way and implement offsetValid and limitValid
-	if b.limitValid {
-		sql.WriteString(" LIMIT ")
-		sql.WriteString(strconv.FormatUint(b.limit, 10))
+	if b.flavor == flavorSQLServer {
+		b.appendFetchToSql(sql)
+	} else if b.standardLimit {
+		appendStandardLimit(sql, b.limit, b.limitValid, b.offset, b.offsetValid)
+	} else {
+		if b.limitValid {
+			sql.WriteString(" LIMIT ")
+			sql.WriteString(strconv.FormatUint(b.limit, 10))
+		}
+
+		if b.offsetValid {
+			sql.WriteString(" OFFSET ")
+			sql.WriteString(strconv.FormatUint(b.offset, 10))
+		}
+	}
+
+	if len(b.settings) > 0 {
+		sql.WriteString(" SETTINGS ")
+		args, err = b.settings.AppendToSql(sql, ", ", args)
+		if err != nil {
+			return
+		}
 	}
 
-	if b.offsetValid {
-		sql.WriteString(" OFFSET ")
-		sql.WriteString(strconv.FormatUint(b.offset, 10))
+	if b.lock.strength != "" {
+		b.lock.AppendToSql(sql)
 	}
 
 	if len(b.suffixes) > 0 {
 		sql.WriteString(" ")
-		args, _ = b.suffixes.AppendToSql(sql, " ", args)
+		args, err = b.suffixes.AppendToSql(sql, " ", args)
+		if err != nil {
+			return
+		}
 	}
 
-	sqlStr, err = b.placeholderFormat.ReplacePlaceholders(sql.String())
+	sqlStr = sql.String()
 	return
+}
+
+// MaxConditions makes ToSql fail when the WHERE clause has more than n
+// conditions, counting each member of And/Or trees and each key of map based
+// predicates. Zero means unlimited, which is the default.
+func (b *SelectBuilder) MaxConditions(n int) *SelectBuilder {
+	b.limits.maxConditions = n
+	return b
+}
+
+// MaxJoins makes ToSql fail when the query has more than n joins. Zero means
+// unlimited, which is the default.
+func (b *SelectBuilder) MaxJoins(n int) *SelectBuilder {
+	b.limits.maxJoins = n
+	return b
+}
+
+// MaxColumns makes ToSql fail when the query has more than n result columns.
+// Zero means unlimited, which is the default.
+func (b *SelectBuilder) MaxColumns(n int) *SelectBuilder {
+	b.limits.maxColumns = n
+	return b
+}
 
+// Comment adds a "/* text */" comment right after the SELECT keyword, e.g. to
+// tag queries for monitoring. Comment delimiters in text are neutralized.
+func (b *SelectBuilder) Comment(text string) *SelectBuilder {
+	b.comment = text
+	return b
 }
 
 // Prefix adds an expression to the beginning of the query
@@ -143,9 +548,58 @@ func (b *SelectBuilder) Prefix(sql string, args ...interface{}) *SelectBuilder {
 	return b
 }
 
+// With adds a common table expression to the WITH clause of the query.
+func (b *SelectBuilder) With(name string, sub Sqlizer) *SelectBuilder {
+	b.ctes = append(b.ctes, cte{name: name, sub: sub})
+	return b
+}
+
+// WithRecursive adds a recursive common table expression to the WITH clause
+// of the query, which then renders as WITH RECURSIVE.
+func (b *SelectBuilder) WithRecursive(name string, sub Sqlizer) *SelectBuilder {
+	b.ctes = append(b.ctes, cte{name: name, sub: sub, recursive: true})
+	return b
+}
+
+// WithColumns is like With, but names the columns of the common table
+// expression: WITH name(a, b) AS (...).
+func (b *SelectBuilder) WithColumns(name string, columns []string, sub Sqlizer) *SelectBuilder {
+	b.ctes = append(b.ctes, cte{name: name, columns: columns, sub: sub})
+	return b
+}
+
+// WithRecursiveColumns is like WithRecursive, but names the columns of the
+// common table expression.
+func (b *SelectBuilder) WithRecursiveColumns(name string, columns []string, sub Sqlizer) *SelectBuilder {
+	b.ctes = append(b.ctes, cte{name: name, columns: columns, sub: sub, recursive: true})
+	return b
+}
+
 // Distinct adds a DISTINCT clause to the query.
+//
+// Distinct and DistinctOn are mutually exclusive, the last call wins.
 func (b *SelectBuilder) Distinct() *SelectBuilder {
 	b.distinct = true
+	b.distinctOn = nil
+
+	return b
+}
+
+// DistinctIf adds a DISTINCT clause to the query when cond is true and is a
+// no-op otherwise.
+func (b *SelectBuilder) DistinctIf(cond bool) *SelectBuilder {
+	if !cond {
+		return b
+	}
+	return b.Distinct()
+}
+
+// DistinctOn adds a DISTINCT ON (columns) clause to the query.
+//
+// SELECT DISTINCT ON is PostgreSQL specific extension
+func (b *SelectBuilder) DistinctOn(columns ...string) *SelectBuilder {
+	b.distinct = false
+	b.distinctOn = columns
 
 	return b
 }
@@ -161,12 +615,24 @@ func (b *SelectBuilder) Options(options ...string) *SelectBuilder {
 // Columns adds result columns to the query.
 func (b *SelectBuilder) Columns(columns ...string) *SelectBuilder {
 	for _, str := range columns {
-		b.columns = append(b.columns, newPart(str))
+		b.columns = append(b.columns, identifier{name: str})
 	}
 
 	return b
 }
 
+// RemoveColumns removes all result columns from the query, including
+// columns added with Column and their args.
+func (b *SelectBuilder) RemoveColumns() *SelectBuilder {
+	b.columns = nil
+	return b
+}
+
+// ResetColumns replaces the result columns of the query with columns.
+func (b *SelectBuilder) ResetColumns(columns ...string) *SelectBuilder {
+	return b.RemoveColumns().Columns(columns...)
+}
+
 // Column adds a result column to the query.
 // Unlike Columns, Column accepts args which will be bound to placeholders in
 // the columns string, for example:
@@ -177,6 +643,44 @@ func (b *SelectBuilder) Column(column interface{}, args ...interface{}) *SelectB
 	return b
 }
 
+// Pivot adds one summed CASE column per category to the query, for example:
+//   Pivot("amount", "type", []string{"a"})
+// adds
+//   SUM(CASE WHEN type = ? THEN amount END) AS a_total
+// with the category bound as an arg. The aliases are built from the
+// categories, so ToSql fails if one is not a plain identifier.
+func (b *SelectBuilder) Pivot(valueCol, categoryCol string, categories []string) *SelectBuilder {
+	for _, category := range categories {
+		alias := category + "_total"
+		if !isPlainIdentifier(alias) {
+			b.err = buildErrorf(CodeInvalidClause, "pivot alias %q is not a plain identifier", alias)
+			return b
+		}
+		when := Case().When(Expr(categoryCol+" = ?", category), valueCol)
+		b.Column(Expr("SUM(?) AS "+alias, when))
+	}
+	return b
+}
+
+// Into sets the table the result rows are written to, rendered as
+// "SELECT ... INTO table FROM ...".
+// Ex:
+//     Select("*").Into("users_archive").From("users").Where("deleted")
+//     == "SELECT * INTO users_archive FROM users WHERE deleted"
+func (b *SelectBuilder) Into(table string) *SelectBuilder {
+	b.into = table
+	b.intoTemp = false
+	return b
+}
+
+// IntoTemp is like Into but writes the result rows to a new temporary table,
+// rendered as "SELECT ... INTO TEMP table FROM ..." (PostgreSQL).
+func (b *SelectBuilder) IntoTemp(table string) *SelectBuilder {
+	b.into = table
+	b.intoTemp = true
+	return b
+}
+
 // From sets the FROM clause of the query.
 func (b *SelectBuilder) From(tables ...string) *SelectBuilder {
 	parts := make([]Sqlizer, len(tables))
@@ -188,12 +692,57 @@ func (b *SelectBuilder) From(tables ...string) *SelectBuilder {
 	return b
 }
 
-// FromSelect sets a subquery into the FROM clause of the query.
+// FromAs adds table to the FROM clause of the query under alias, rendered as
+// "table AS alias". Unlike a table given to From, table and alias are quoted
+// with the QuoteStyle of the query.
+// Ex:
+//     Select("u.name", "o.total").FromAs("users", "u").FromAs("orders", "o")
+//     == "SELECT u.name, o.total FROM users AS u, orders AS o"
+func (b *SelectBuilder) FromAs(table, alias string) *SelectBuilder {
+	b.fromParts = append(b.fromParts, identifier{name: table, alias: alias})
+	return b
+}
+
+// TableSample adds a "TABLESAMPLE method (percent)" clause to the table last
+// added to the FROM clause, to read a random sample of about percent
+// percent of its rows. percent is bound as an arg.
+// Ex:
+//     Select("avg(amount)").From("payments").TableSample("SYSTEM", 1.5)
+//     == "SELECT avg(amount) FROM payments TABLESAMPLE SYSTEM (?)"
+//
+// TABLESAMPLE is supported by PostgreSQL and SQL Server.
+func (b *SelectBuilder) TableSample(method string, percent float64) *SelectBuilder {
+	n := len(b.fromParts)
+	if n == 0 {
+		b.err = buildErrorf(CodeNoTable, "TableSample requires a table added with From")
+		return b
+	}
+	sample := Expr("? TABLESAMPLE "+method+" (?)", b.fromParts[n-1], percent)
+	b.fromParts = append(b.fromParts[:n-1:n-1], sample)
+	return b
+}
+
+// FromSelect sets a subquery into the FROM clause of the query, rendered as
+// "(subquery) AS alias" with the subquery args placed before WHERE args.
+//
+// From and FromSelect accumulate: all tables and subqueries are listed in
+// the FROM clause separated by commas, in the order they were added.
 func (b *SelectBuilder) FromSelect(from *SelectBuilder, alias string) *SelectBuilder {
 	b.fromParts = append(b.fromParts, Alias(from, alias))
 	return b
 }
 
+// FromClause adds from, rendered as is, to the FROM clause of the query, for
+// table expressions such as a table function call. Its args are placed
+// before WHERE args.
+// Ex:
+//     Select("n").FromClause(Expr("generate_series(1, ?) AS n", 10))
+//     == "SELECT n FROM generate_series(1, ?) AS n"
+func (b *SelectBuilder) FromClause(from Sqlizer) *SelectBuilder {
+	b.fromParts = append(b.fromParts, from)
+	return b
+}
+
 // JoinClause adds a join clause to the query.
 func (b *SelectBuilder) JoinClause(pred interface{}, args ...interface{}) *SelectBuilder {
 	b.joins = append(b.joins, newPart(pred, args...))
@@ -206,6 +755,20 @@ func (b *SelectBuilder) Join(join string, rest ...interface{}) *SelectBuilder {
 	return b.JoinClause("JOIN "+join, rest...)
 }
 
+// InnerJoin adds a INNER JOIN clause to the query.
+func (b *SelectBuilder) InnerJoin(join string, rest ...interface{}) *SelectBuilder {
+	return b.JoinClause("INNER JOIN "+join, rest...)
+}
+
+// JoinIf adds a JOIN clause to the query when cond is true and is a no-op
+// otherwise.
+func (b *SelectBuilder) JoinIf(cond bool, join string, rest ...interface{}) *SelectBuilder {
+	if !cond {
+		return b
+	}
+	return b.Join(join, rest...)
+}
+
 // LeftJoin adds a LEFT JOIN clause to the query.
 func (b *SelectBuilder) LeftJoin(join string, rest ...interface{}) *SelectBuilder {
 	return b.JoinClause("LEFT JOIN "+join, rest...)
@@ -216,6 +779,87 @@ func (b *SelectBuilder) RightJoin(join string, rest ...interface{}) *SelectBuild
 	return b.JoinClause("RIGHT JOIN "+join, rest...)
 }
 
+// FullJoin adds a FULL OUTER JOIN clause to the query.
+func (b *SelectBuilder) FullJoin(join string, rest ...interface{}) *SelectBuilder {
+	return b.JoinClause("FULL OUTER JOIN "+join, rest...)
+}
+
+// JoinOn adds a "JOIN table ON on" clause to the query, with on built from
+// predicate helpers instead of a string. The args of on are placed in join
+// order. An on that renders empty, such as And{} or nil, renders "ON true".
+// Ex:
+//     .JoinOn("orders o", Eq{"o.uid": Expr("u.id")})
+//     == "JOIN orders o ON o.uid = u.id"
+func (b *SelectBuilder) JoinOn(table string, on Sqlizer) *SelectBuilder {
+	return b.joinOn("JOIN", table, on)
+}
+
+// InnerJoinOn adds a "INNER JOIN table ON on" clause to the query. See JoinOn.
+func (b *SelectBuilder) InnerJoinOn(table string, on Sqlizer) *SelectBuilder {
+	return b.joinOn("INNER JOIN", table, on)
+}
+
+// LeftJoinOn adds a "LEFT JOIN table ON on" clause to the query. See JoinOn.
+func (b *SelectBuilder) LeftJoinOn(table string, on Sqlizer) *SelectBuilder {
+	return b.joinOn("LEFT JOIN", table, on)
+}
+
+// RightJoinOn adds a "RIGHT JOIN table ON on" clause to the query. See JoinOn.
+func (b *SelectBuilder) RightJoinOn(table string, on Sqlizer) *SelectBuilder {
+	return b.joinOn("RIGHT JOIN", table, on)
+}
+
+func (b *SelectBuilder) joinOn(join, table string, on Sqlizer) *SelectBuilder {
+	return b.JoinClause(Expr(join+" "+table+" ON ?", joinCondition{on}))
+}
+
+// joinCondition renders the ON condition of a join, or "true" if it is nil
+// or empty.
+type joinCondition struct {
+	on Sqlizer
+}
+
+func (c joinCondition) ToSql() (string, []interface{}, error) {
+	if c.on == nil {
+		return "true", nil, nil
+	}
+	sql, args, err := nestedToSql(c.on)
+	if err == nil && sql == "" {
+		sql = "true"
+	}
+	return sql, args, err
+}
+
+// JoinLateral adds a "JOIN LATERAL (sub) alias ON on" clause to the query.
+// sub may reference columns of the tables joined before it. An empty on
+// renders "ON true".
+//
+// LATERAL is supported by PostgreSQL and MySQL 8.
+func (b *SelectBuilder) JoinLateral(sub *SelectBuilder, alias string, on string, onArgs ...interface{}) *SelectBuilder {
+	return b.joinLateral("JOIN", sub, alias, on, onArgs)
+}
+
+// LeftJoinLateral adds a "LEFT JOIN LATERAL (sub) alias ON on" clause to the
+// query.
+//
+// See JoinLateral.
+func (b *SelectBuilder) LeftJoinLateral(sub *SelectBuilder, alias string, on string, onArgs ...interface{}) *SelectBuilder {
+	return b.joinLateral("LEFT JOIN", sub, alias, on, onArgs)
+}
+
+func (b *SelectBuilder) joinLateral(join string, sub *SelectBuilder, alias, on string, onArgs []interface{}) *SelectBuilder {
+	if on == "" {
+		on = "true"
+	}
+	args := append([]interface{}{sub}, onArgs...)
+	return b.JoinClause(Expr(join+" LATERAL (?) "+alias+" ON "+on, args...))
+}
+
+// CrossJoin adds a CROSS JOIN clause to the query.
+func (b *SelectBuilder) CrossJoin(table string) *SelectBuilder {
+	return b.JoinClause("CROSS JOIN " + table)
+}
+
 // Where adds an expression to the WHERE clause of the query.
 //
 // Expressions are ANDed together in the generated SQL.
@@ -241,26 +885,315 @@ func (b *SelectBuilder) Where(pred interface{}, args ...interface{}) *SelectBuil
 	return b
 }
 
+// WhereIf adds a WHERE expression to the query when cond is true and is a
+// no-op otherwise, so that optional filters do not break the chain:
+//
+//   Select("*").From("users").
+//       WhereIf(name != "", Eq{"name": name}).
+//       WhereIf(minAge > 0, "age >= ?", minAge)
+func (b *SelectBuilder) WhereIf(cond bool, pred interface{}, args ...interface{}) *SelectBuilder {
+	if !cond {
+		return b
+	}
+	return b.Where(pred, args...)
+}
+
+// Union combines the query with other using UNION.
+//
+// other may be any Sqlizer, such as another SelectBuilder or a Values list.
+// When set operations are used, both sides are parenthesized and ORDER BY,
+// LIMIT and OFFSET of the receiver apply to the combined result.
+func (b *SelectBuilder) Union(other Sqlizer) *SelectBuilder {
+	b.unions = append(b.unions, setOperation{"UNION", other})
+	return b
+}
+
+// UnionAll combines the query with other using UNION ALL.
+//
+// See Union.
+func (b *SelectBuilder) UnionAll(other Sqlizer) *SelectBuilder {
+	b.unions = append(b.unions, setOperation{"UNION ALL", other})
+	return b
+}
+
+// Intersect combines the query with other using INTERSECT.
+//
+// See Union.
+func (b *SelectBuilder) Intersect(other Sqlizer) *SelectBuilder {
+	b.unions = append(b.unions, setOperation{"INTERSECT", other})
+	return b
+}
+
+// Except combines the query with other using EXCEPT.
+//
+// See Union.
+func (b *SelectBuilder) Except(other Sqlizer) *SelectBuilder {
+	b.unions = append(b.unions, setOperation{"EXCEPT", other})
+	return b
+}
+
+// Prewhere adds an expression to the PREWHERE clause of the query, which is
+// rendered between the joins and WHERE.
+//
+// See Where.
+//
+// SELECT ... PREWHERE is ClickHouse specific extension
+func (b *SelectBuilder) Prewhere(pred interface{}, args ...interface{}) *SelectBuilder {
+	b.prewhere = append(b.prewhere, newWherePart(pred, args...))
+	return b
+}
+
 // GroupBy adds GROUP BY expressions to the query.
 func (b *SelectBuilder) GroupBy(groupBys ...string) *SelectBuilder {
-	b.groupBys = append(b.groupBys, groupBys...)
+	for _, groupBy := range groupBys {
+		b.groupBys = append(b.groupBys, identifier{name: groupBy})
+	}
+	return b
+}
+
+// GroupByExpr adds a GROUP BY expression with bound args to the query. Its
+// args are placed after WHERE args and before HAVING args.
+// Ex:
+//     .GroupByExpr("width_bucket(amount, ?, ?, ?)", 0, 1000, 10)
+//
+// Its placeholders are new parameters, so repeating a selected expression
+// with args does not group by it: Postgres rejects
+// "SELECT date_trunc($1, t) ... GROUP BY date_trunc($2, t)", as $1 and $2
+// may differ. Group such a column by its position, GroupBy("1"), instead.
+func (b *SelectBuilder) GroupByExpr(sql string, args ...interface{}) *SelectBuilder {
+	b.groupBys = append(b.groupBys, newPart(sql, args...))
+	return b
+}
+
+// GroupByRollup adds "ROLLUP(cols)" to the GROUP BY clause of the query. It
+// can be combined with plain GroupBy columns.
+func (b *SelectBuilder) GroupByRollup(cols ...string) *SelectBuilder {
+	b.groupBys = append(b.groupBys, newPart(rollup(cols)))
+	return b
+}
+
+// GroupByCube adds "CUBE(cols)" to the GROUP BY clause of the query.
+func (b *SelectBuilder) GroupByCube(cols ...string) *SelectBuilder {
+	b.groupBys = append(b.groupBys, newPart(cube(cols)))
+	return b
+}
+
+// GroupBySets adds "GROUPING SETS ((a, b), (c), ())" to the GROUP BY clause
+// of the query, one parenthesized set per argument. An empty set groups the
+// grand total.
+func (b *SelectBuilder) GroupBySets(sets ...[]string) *SelectBuilder {
+	b.groupBys = append(b.groupBys, newPart(groupingSets(sets)))
 	return b
 }
 
 // Having adds an expression to the HAVING clause of the query.
 //
-// See Where.
+// It accepts the same predicates as Where, so comparison helpers work on
+// aggregates, e.g. Having(Gt{"COUNT(*)": 5}) renders "HAVING COUNT(*) > ?".
 func (b *SelectBuilder) Having(pred interface{}, rest ...interface{}) *SelectBuilder {
 	b.havingParts = append(b.havingParts, newWherePart(pred, rest...))
 	return b
 }
 
+// HavingIf adds an expression to the HAVING clause of the query when cond is
+// true and is a no-op otherwise.
+func (b *SelectBuilder) HavingIf(cond bool, pred interface{}, rest ...interface{}) *SelectBuilder {
+	if !cond {
+		return b
+	}
+	return b.Having(pred, rest...)
+}
+
+// HavingEq adds "aggregate = ?" to the HAVING clause of the query, e.g.
+//   HavingEq(Expr("COUNT(*)"), 1)
+// The aggregate args are placed before value.
+func (b *SelectBuilder) HavingEq(aggregate Sqlizer, value interface{}) *SelectBuilder {
+	return b.Having(compare{aggregate, "=", value})
+}
+
+// HavingLt adds "aggregate < ?" to the HAVING clause of the query.
+//
+// See HavingEq.
+func (b *SelectBuilder) HavingLt(aggregate Sqlizer, value interface{}) *SelectBuilder {
+	return b.Having(compare{aggregate, "<", value})
+}
+
+// HavingLtOrEq adds "aggregate <= ?" to the HAVING clause of the query.
+//
+// See HavingEq.
+func (b *SelectBuilder) HavingLtOrEq(aggregate Sqlizer, value interface{}) *SelectBuilder {
+	return b.Having(compare{aggregate, "<=", value})
+}
+
+// HavingGt adds "aggregate > ?" to the HAVING clause of the query.
+//
+// See HavingEq.
+func (b *SelectBuilder) HavingGt(aggregate Sqlizer, value interface{}) *SelectBuilder {
+	return b.Having(compare{aggregate, ">", value})
+}
+
+// HavingGtOrEq adds "aggregate >= ?" to the HAVING clause of the query.
+//
+// See HavingEq.
+func (b *SelectBuilder) HavingGtOrEq(aggregate Sqlizer, value interface{}) *SelectBuilder {
+	return b.Having(compare{aggregate, ">=", value})
+}
+
+// Window defines a named window in the WINDOW clause of the query, to be
+// referenced by columns built with Over:
+//   Window("w", "PARTITION BY dept ORDER BY salary DESC")
+// renders
+//   WINDOW w AS (PARTITION BY dept ORDER BY salary DESC)
+// ToSql fails if a column references a window that is not defined.
+func (b *SelectBuilder) Window(name, spec string) *SelectBuilder {
+	b.windows = append(b.windows, namedWindow{name: name, spec: spec})
+	return b
+}
+
 // OrderBy adds ORDER BY expressions to the query.
 func (b *SelectBuilder) OrderBy(orderBys ...string) *SelectBuilder {
-	b.orderBys = append(b.orderBys, orderBys...)
+	for _, orderBy := range orderBys {
+		b.orderBys = append(b.orderBys, identifier{name: orderBy, order: true})
+	}
+	return b
+}
+
+// OrderByTerms adds ORDER BY terms built by Asc and Desc to the query. Unlike
+// the strings given to OrderBy, their NULLS FIRST/LAST ordering is rendered
+// for the Dialect of the query:
+//
+//   OrderByTerms(Desc("last_login").NullsLast())
+//   // Postgres: ORDER BY last_login DESC NULLS LAST
+//   // MySQL:    ORDER BY last_login IS NULL, last_login DESC
+func (b *SelectBuilder) OrderByTerms(terms ...OrderTerm) *SelectBuilder {
+	for _, t := range terms {
+		b.orderBys = append(b.orderBys, t)
+	}
+	return b
+}
+
+// OrderByIf adds ORDER BY expressions to the query when cond is true and is
+// a no-op otherwise.
+func (b *SelectBuilder) OrderByIf(cond bool, orderBys ...string) *SelectBuilder {
+	if !cond {
+		return b
+	}
+	return b.OrderBy(orderBys...)
+}
+
+// OrderBySpec adds ORDER BY expressions parsed from a sort spec such as
+// "-created_at,name", as commonly sent in API requests. Keys are separated
+// by commas and a leading "-" sorts descending.
+//
+// Each key is mapped to an ORDER BY expression through whitelist, so spec
+// never reaches the SQL verbatim. Unknown keys are skipped if skipUnknown is
+// set, otherwise ToSql returns an error.
+//
+//   OrderBySpec("-created,name", map[string]string{"created": "created_at", "name": "u.name"}, false)
+//   // ORDER BY created_at DESC, u.name ASC
+func (b *SelectBuilder) OrderBySpec(spec string, whitelist map[string]string, skipUnknown bool) *SelectBuilder {
+	for _, key := range strings.Split(spec, ",") {
+		key = strings.TrimSpace(key)
+		dir := "ASC"
+		if strings.HasPrefix(key, "-") {
+			key, dir = key[1:], "DESC"
+		} else if strings.HasPrefix(key, "+") {
+			key = key[1:]
+		}
+		if key == "" {
+			continue
+		}
+
+		column, ok := whitelist[key]
+		if !ok {
+			if skipUnknown {
+				continue
+			}
+			b.err = buildErrorf(CodeInvalidClause, "unknown sort key %q", key)
+			return b
+		}
+		b.orderBys = append(b.orderBys, newPart(column+" "+dir))
+	}
+	return b
+}
+
+// OrderByPosition adds ORDER BY expressions referencing result columns by
+// their 1-based position, e.g. OrderByPosition(1, 3) renders "ORDER BY 1, 3".
+//
+// ToSql fails if a position is not positive or, when the number of result
+// columns is known (no wildcard columns), exceeds it.
+func (b *SelectBuilder) OrderByPosition(positions ...int) *SelectBuilder {
+	for _, pos := range positions {
+		b.orderBys = append(b.orderBys, newPart(strconv.Itoa(pos)))
+	}
+	b.orderByPositions = append(b.orderByPositions, positions...)
 	return b
 }
 
+func (b *SelectBuilder) checkOrderByPositions() error {
+	count := b.columnCount()
+	for _, pos := range b.orderByPositions {
+		if pos < 1 {
+			return buildErrorf(CodeInvalidClause, "order by position must be positive, got %d", pos)
+		}
+		if count >= 0 && pos > count {
+			return buildErrorf(CodeInvalidClause, "order by position %d is out of range, query has %d columns", pos, count)
+		}
+	}
+	return nil
+}
+
+// columnCount returns the number of result columns, or -1 when a wildcard
+// column makes it unknown.
+func (b *SelectBuilder) columnCount() int {
+	for _, c := range b.columns {
+		if id, ok := c.(identifier); ok && strings.HasSuffix(id.name, "*") {
+			return -1
+		}
+		if p, ok := c.(*part); ok {
+			if s, ok := p.pred.(string); ok && strings.HasSuffix(s, "*") {
+				return -1
+			}
+		}
+	}
+	return len(b.columns)
+}
+
+// useTop reports whether the limit is rendered as SELECT TOP, which SQL Server
+// uses when there is no offset.
+func (b *SelectBuilder) useTop() bool {
+	return b.flavor == flavorSQLServer && b.limitValid && !b.offsetValid
+}
+
+// appendFetchToSql writes the SQL Server form of OFFSET and LIMIT,
+// "OFFSET n ROWS FETCH NEXT m ROWS ONLY", which requires an ORDER BY clause.
+func (b *SelectBuilder) appendFetchToSql(w io.Writer) {
+	if !b.offsetValid {
+		return
+	}
+	fmt.Fprintf(w, " OFFSET %d ROWS", b.offset)
+	if b.limitValid {
+		fmt.Fprintf(w, " FETCH NEXT %d ROWS ONLY", b.limit)
+	}
+}
+
+// appendStandardLimit writes the SQL standard form of OFFSET and LIMIT,
+// "OFFSET n ROWS FETCH NEXT m ROWS ONLY", or "FETCH FIRST m ROWS ONLY" without
+// an offset.
+func appendStandardLimit(w io.Writer, limit uint64, limitValid bool, offset uint64, offsetValid bool) {
+	if offsetValid {
+		fmt.Fprintf(w, " OFFSET %d ROWS", offset)
+	}
+	if !limitValid {
+		return
+	}
+	if offsetValid {
+		fmt.Fprintf(w, " FETCH NEXT %d ROWS ONLY", limit)
+	} else {
+		fmt.Fprintf(w, " FETCH FIRST %d ROWS ONLY", limit)
+	}
+}
+
 // Limit sets a LIMIT clause on the query.
 func (b *SelectBuilder) Limit(limit uint64) *SelectBuilder {
 	b.limit = limit
@@ -268,6 +1201,15 @@ func (b *SelectBuilder) Limit(limit uint64) *SelectBuilder {
 	return b
 }
 
+// LimitIf adds a LIMIT clause to the query when cond is true and is
+// a no-op otherwise.
+func (b *SelectBuilder) LimitIf(cond bool, limit uint64) *SelectBuilder {
+	if !cond {
+		return b
+	}
+	return b.Limit(limit)
+}
+
 // Offset sets a OFFSET clause on the query.
 func (b *SelectBuilder) Offset(offset uint64) *SelectBuilder {
 	b.offset = offset
@@ -275,6 +1217,96 @@ func (b *SelectBuilder) Offset(offset uint64) *SelectBuilder {
 	return b
 }
 
+// ClearLimit removes the LIMIT clause set by Limit. Unlike Limit(0), which
+// renders "LIMIT 0", it leaves the query without a LIMIT clause.
+func (b *SelectBuilder) ClearLimit() *SelectBuilder {
+	b.limit = 0
+	b.limitValid = false
+	return b
+}
+
+// ClearOffset removes the OFFSET clause set by Offset.
+func (b *SelectBuilder) ClearOffset() *SelectBuilder {
+	b.offset = 0
+	b.offsetValid = false
+	return b
+}
+
+// Paginate sets LIMIT and OFFSET for a 1-based page of pageSize rows. Pages
+// 0 and 1 both select the first page, with OFFSET 0.
+func (b *SelectBuilder) Paginate(page, pageSize uint64) *SelectBuilder {
+	var offset uint64
+	if page > 1 {
+		offset = (page - 1) * pageSize
+		if pageSize != 0 && offset/pageSize != page-1 {
+			offset = math.MaxUint64
+		}
+	}
+	return b.Limit(pageSize).Offset(offset)
+}
+
+// Settings adds "SETTINGS key = ?" query level settings for each key/value
+// pair in settings, in sorted key order. They are rendered after LIMIT and
+// OFFSET.
+//
+// SELECT ... SETTINGS is ClickHouse specific extension
+func (b *SelectBuilder) Settings(settings map[string]interface{}) *SelectBuilder {
+	for _, key := range sortedKeys(settings) {
+		b.settings = append(b.settings, setClause{column: key, value: settings[key]})
+	}
+	return b
+}
+
+// ForUpdate adds a FOR UPDATE locking clause to the query. It is rendered
+// after LIMIT and OFFSET and before any suffixes.
+func (b *SelectBuilder) ForUpdate() *SelectBuilder {
+	b.lock.strength = "UPDATE"
+	return b
+}
+
+// ForShare adds a FOR SHARE locking clause to the query.
+//
+// See ForUpdate.
+func (b *SelectBuilder) ForShare() *SelectBuilder {
+	b.lock.strength = "SHARE"
+	return b
+}
+
+// ForNoKeyUpdate adds a FOR NO KEY UPDATE locking clause to the query.
+//
+// See ForUpdate.
+func (b *SelectBuilder) ForNoKeyUpdate() *SelectBuilder {
+	b.lock.strength = "NO KEY UPDATE"
+	return b
+}
+
+// ForKeyShare adds a FOR KEY SHARE locking clause to the query.
+//
+// See ForUpdate.
+func (b *SelectBuilder) ForKeyShare() *SelectBuilder {
+	b.lock.strength = "KEY SHARE"
+	return b
+}
+
+// Of restricts the locking clause to the given tables, e.g.
+// "FOR UPDATE OF jobs".
+func (b *SelectBuilder) Of(tables ...string) *SelectBuilder {
+	b.lock.of = append(b.lock.of, tables...)
+	return b
+}
+
+// NoWait makes the locking clause fail instead of waiting for locked rows.
+func (b *SelectBuilder) NoWait() *SelectBuilder {
+	b.lock.wait = "NOWAIT"
+	return b
+}
+
+// SkipLocked makes the locking clause skip rows that are already locked.
+func (b *SelectBuilder) SkipLocked() *SelectBuilder {
+	b.lock.wait = "SKIP LOCKED"
+	return b
+}
+
 // Suffix adds an expression to the end of the query
 func (b *SelectBuilder) Suffix(sql string, args ...interface{}) *SelectBuilder {
 	b.suffixes = append(b.suffixes, Expr(sql, args...))
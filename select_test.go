@@ -0,0 +1,1059 @@
+package bsql
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectBuilderPivot(t *testing.T) {
+	b := Select("region").
+		Pivot("amount", "type", []string{"a", "b", "c"}).
+		From("sales").
+		GroupBy("region")
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT region, " +
+		"SUM(CASE WHEN type = ? THEN amount END) AS a_total, " +
+		"SUM(CASE WHEN type = ? THEN amount END) AS b_total, " +
+		"SUM(CASE WHEN type = ? THEN amount END) AS c_total " +
+		"FROM sales GROUP BY region"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{"a", "b", "c"}
+	assert.Equal(t, expectedArgs, args)
+
+	_, _, err = Select("region").Pivot("amount", "type", []string{"a", "x; DROP TABLE sales; --"}).From("sales").ToSql()
+	assert.ErrorIs(t, err, ErrInvalidClause)
+}
+
+func TestSelectBuilderWhereLike(t *testing.T) {
+	b := Select("id").From("users").Where(Like{"name": "%foo%"}).PlaceholderFormat(Dollar)
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT id FROM users WHERE name LIKE $1"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{"%foo%"}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestSelectBuilderWhereInSubquery(t *testing.T) {
+	sub := Select("id").From("groups").Where(Eq{"owner": 7}).PlaceholderFormat(Dollar)
+	b := Select("*").From("users").
+		Where(Eq{"active": true}).
+		Where(Eq{"group_id": sub, "role": []string{"a", "b"}}).
+		PlaceholderFormat(Dollar)
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT * FROM users WHERE active = $1 AND " +
+		"group_id IN (SELECT id FROM groups WHERE owner = $2) AND role IN ($3,$4)"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{true, 7, "a", "b"}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestSelectBuilderBetween(t *testing.T) {
+	b := Select("day", "SUM(amount)").From("sales").
+		Where(Between("day", "2022-01-01", "2022-01-31")).
+		GroupBy("day").
+		Having(Between("SUM(amount)", 100, 200)).
+		PlaceholderFormat(Dollar)
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT day, SUM(amount) FROM sales WHERE day BETWEEN $1 AND $2 " +
+		"GROUP BY day HAVING SUM(amount) BETWEEN $3 AND $4"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{"2022-01-01", "2022-01-31", 100, 200}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestSelectBuilderJoinIf(t *testing.T) {
+	for _, withTags := range []bool{true, false} {
+		b := Select("p.id").From("posts p").
+			JoinIf(withTags, "tags t ON t.post_id = p.id AND t.kind = ?", "topic").
+			Where(Eq{"p.author": 3})
+		sql, args, err := b.ToSql()
+		assert.NoError(t, err)
+
+		if withTags {
+			assert.Equal(t, "SELECT p.id FROM posts p JOIN tags t ON t.post_id = p.id AND t.kind = ? WHERE p.author = ?", sql)
+			assert.Equal(t, []interface{}{"topic", 3}, args)
+		} else {
+			assert.Equal(t, "SELECT p.id FROM posts p WHERE p.author = ?", sql)
+			assert.Equal(t, []interface{}{3}, args)
+		}
+	}
+}
+
+func TestSelectBuilderWith(t *testing.T) {
+	recent := Select("id", "user_id").From("orders").Where(Gt{"created_at": "2022-01-01"})
+	vip := Select("id").From("users").Where(Eq{"tier": "gold"})
+	b := Select("r.id").
+		With("recent", recent).
+		With("vip", vip).
+		From("recent r").
+		Join("vip v ON v.id = r.user_id").
+		Where(Eq{"r.state": "open"}).
+		PlaceholderFormat(Dollar)
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "WITH recent AS (SELECT id, user_id FROM orders WHERE created_at > $1), " +
+		"vip AS (SELECT id FROM users WHERE tier = $2) " +
+		"SELECT r.id FROM recent r JOIN vip v ON v.id = r.user_id WHERE r.state = $3"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{"2022-01-01", "gold", "open"}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestSelectBuilderWithRecursive(t *testing.T) {
+	tree := Expr("SELECT id FROM nodes WHERE id = ? UNION ALL SELECT n.id FROM nodes n JOIN tree t ON n.parent = t.id", 1)
+	b := Select("id").WithRecursive("tree", tree).From("tree")
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "WITH RECURSIVE tree AS (SELECT id FROM nodes WHERE id = ? " +
+		"UNION ALL SELECT n.id FROM nodes n JOIN tree t ON n.parent = t.id) SELECT id FROM tree"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestSelectBuilderWithColumns(t *testing.T) {
+	totals := Select("user_id", "SUM(amount)").From("orders").Where(Eq{"state": "paid"}).GroupBy("user_id")
+	b := Select("uid", "total").
+		WithColumns("totals", []string{"uid", "total"}, totals).
+		From("totals").
+		Where(Gt{"total": 100})
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "WITH totals(uid, total) AS (SELECT user_id, SUM(amount) FROM orders WHERE state = ? GROUP BY user_id) " +
+		"SELECT uid, total FROM totals WHERE total > ?"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{"paid", 100}, args)
+
+	nums := Expr("SELECT 1 UNION ALL SELECT n + 1 FROM t WHERE n < ?", 5)
+	sql, _, err = Select("n").WithRecursiveColumns("t", []string{"n"}, nums).From("t").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "WITH RECURSIVE t(n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM t WHERE n < ?) SELECT n FROM t", sql)
+}
+
+func TestSelectBuilderHavingCompare(t *testing.T) {
+	b := Select("user_id").From("orders").
+		Where(Eq{"state": "paid"}).
+		GroupBy("user_id").
+		HavingGt(Expr("COUNT(*)"), 5).
+		HavingLtOrEq(Expr("SUM(amount * ?)", 2), 1000).
+		PlaceholderFormat(Dollar)
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT user_id FROM orders WHERE state = $1 GROUP BY user_id " +
+		"HAVING COUNT(*) > $2 AND SUM(amount * $3) <= $4"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{"paid", 5, 2, 1000}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestSelectBuilderJoins(t *testing.T) {
+	b := Select("t.id").From("things t").
+		LeftJoin("other o ON o.id = t.oid AND o.x = ?", 1).
+		InnerJoin("owners w ON w.id = t.owner_id AND w.active = ?", true).
+		RightJoin("extra e ON e.tid = t.id").
+		JoinClause(Expr("CROSS JOIN (SELECT ? AS k) k", "key")).
+		Where(Eq{"t.kind": "x"}).
+		PlaceholderFormat(Dollar)
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT t.id FROM things t " +
+		"LEFT JOIN other o ON o.id = t.oid AND o.x = $1 " +
+		"INNER JOIN owners w ON w.id = t.owner_id AND w.active = $2 " +
+		"RIGHT JOIN extra e ON e.tid = t.id " +
+		"CROSS JOIN (SELECT $3 AS k) k " +
+		"WHERE t.kind = $4"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{1, true, "key", "x"}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestSelectBuilderFullJoin(t *testing.T) {
+	sql, args, err := Select("l.id", "r.id").From("ledger l").
+		FullJoin("bank r ON r.ref = l.ref AND r.account = ?", "acc-1").
+		Where(Or{Eq{"l.id": nil}, Eq{"r.id": nil}}).
+		Where("COALESCE(l.day, r.day) >= ?", "2024-01-01").
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT l.id, r.id FROM ledger l " +
+		"FULL OUTER JOIN bank r ON r.ref = l.ref AND r.account = ? " +
+		"WHERE (l.id IS NULL OR r.id IS NULL) AND COALESCE(l.day, r.day) >= ?"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{"acc-1", "2024-01-01"}, args)
+}
+
+func TestSelectBuilderCrossJoin(t *testing.T) {
+	sql, args, err := Select("d.day", "s.name", "c.total").
+		From("stores s").
+		CrossJoin("generate_series(1, 7) d(day)").
+		LeftJoin("counts c ON c.store_id = s.id AND c.day = d.day AND c.kind = ?", "sale").
+		Where(Eq{"s.active": true}).
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT d.day, s.name, c.total FROM stores s " +
+		"CROSS JOIN generate_series(1, 7) d(day) " +
+		"LEFT JOIN counts c ON c.store_id = s.id AND c.day = d.day AND c.kind = ? " +
+		"WHERE s.active = ?"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{"sale", true}, args)
+}
+
+func TestSelectBuilderJoinLateral(t *testing.T) {
+	latest := Select("o.total").From("orders o").
+		Where("o.user_id = u.id AND o.status = ?", "paid").
+		OrderBy("o.created_at DESC").
+		Limit(1)
+	sql, args, err := Select("u.id", "l.total").From("users u").
+		LeftJoinLateral(latest, "l", "l.total > ?", 100).
+		JoinLateral(Select("count(*) AS n").From("logins g").Where("g.user_id = u.id"), "g", "").
+		Where(Eq{"u.team": 5}).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT u.id, l.total FROM users u " +
+		"LEFT JOIN LATERAL (SELECT o.total FROM orders o WHERE o.user_id = u.id AND o.status = $1 " +
+		"ORDER BY o.created_at DESC LIMIT 1) l ON l.total > $2 " +
+		"JOIN LATERAL (SELECT count(*) AS n FROM logins g WHERE g.user_id = u.id) g ON true " +
+		"WHERE u.team = $3"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{"paid", 100, 5}, args)
+}
+
+func TestSelectBuilderMaxConditions(t *testing.T) {
+	b := Select("id").From("t").
+		Where(Eq{"a": 1, "b": 2}).
+		Where(Or{Eq{"c": 3}, Lt{"d": 4}}).
+		MaxConditions(4)
+	_, _, err := b.ToSql()
+	assert.NoError(t, err)
+
+	b.Where("e = ?", 5)
+	_, _, err = b.ToSql()
+	assert.EqualError(t, err, "query has 5 conditions, more than the maximum of 4")
+}
+
+func TestSelectBuilderMaxConditionsConjBuilder(t *testing.T) {
+	_, _, err := Select("id").From("t").
+		Where(AnyOf(map[string]interface{}{"a": 1, "b": 2}, map[string]interface{}{"c": 3})).
+		MaxConditions(2).
+		ToSql()
+	assert.EqualError(t, err, "query has 3 conditions, more than the maximum of 2")
+
+	_, _, err = Select("id").From("t").
+		Where(NewOr().Add(Eq{"a": 1}, NewAnd().Add(Eq{"b": 2}, Lt{"c": 3}))).
+		MaxConditions(2).
+		ToSql()
+	assert.EqualError(t, err, "query has 3 conditions, more than the maximum of 2")
+}
+
+func TestSelectBuilderMaxConditionsNot(t *testing.T) {
+	_, _, err := Select("id").From("t").
+		Where(Not(Or{Eq{"a": 1}, Eq{"b": 2}, Eq{"c": 3}})).
+		MaxConditions(2).
+		ToSql()
+	assert.EqualError(t, err, "query has 3 conditions, more than the maximum of 2")
+}
+
+func TestSelectBuilderPreferAnyArray(t *testing.T) {
+	ids := []int{1, 2, 3}
+	sb := StatementBuilder.Dialect(Postgres)
+
+	sql, args, err := sb.Select("name").From("users").Where(Eq{"id": ids}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT "name" FROM users WHERE id IN ($1,$2,$3)`, sql)
+	assert.Equal(t, []interface{}{1, 2, 3}, args)
+
+	sql, args, err = sb.PreferAnyArray(true).Select("name").From("users").Where(Eq{"id": ids}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT "name" FROM users WHERE id = ANY($1)`, sql)
+	assert.Equal(t, []interface{}{ids}, args)
+
+	sql, args, err = sb.Select("name").From("users").
+		Where(Or{Eq{"team": []string{"a", "b"}}, Not(NotEq{"id": ids})}).
+		Where(map[string]interface{}{"state": "active", "tag": []string{}}).
+		PreferAnyArray(true).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT "name" FROM users WHERE (team = ANY($1) OR NOT (id <> ALL($2))) AND state = $3 AND (1=0)`, sql)
+	assert.Equal(t, []interface{}{[]string{"a", "b"}, ids, "active"}, args)
+
+	_, _, err = Select("name").From("users").Where(Eq{"id": ids}).PreferAnyArray(true).ToSql()
+	assert.EqualError(t, err, "PreferAnyArray requires the Postgres dialect")
+}
+
+func TestSelectBuilderMaxJoinsAndColumns(t *testing.T) {
+	_, _, err := Select("a", "b").From("t").Join("u USING (id)").Join("v USING (id)").MaxJoins(1).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Select("a", "b", "c").From("t").MaxColumns(2).ToSql()
+	assert.Error(t, err)
+}
+
+func TestSelectBuilderUnionAll(t *testing.T) {
+	b := Select("id", "name").From("users").Where(Eq{"team": 1}).
+		UnionAll(Select("id", "name").From("admins").Where(Eq{"level": 2})).
+		UnionAll(Select("id", "name").From("guests").Where(Eq{"invited_by": 3})).
+		OrderBy("name").
+		Limit(10).
+		PlaceholderFormat(Dollar)
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "(SELECT id, name FROM users WHERE team = $1) " +
+		"UNION ALL (SELECT id, name FROM admins WHERE level = $2) " +
+		"UNION ALL (SELECT id, name FROM guests WHERE invited_by = $3) " +
+		"ORDER BY name LIMIT 10"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{1, 2, 3}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestSelectBuilderUnionValues(t *testing.T) {
+	b := Select("id", "name").From("teams").Where(Eq{"active": true}).
+		UnionAll(Values([]interface{}{0, "all"}, []interface{}{-1, Expr("upper(?)", "none")})).
+		PlaceholderFormat(Dollar)
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "(SELECT id, name FROM teams WHERE active = $1) " +
+		"UNION ALL (VALUES ($2,$3),($4,upper($5)))"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{true, 0, "all", -1, "none"}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestValuesEmpty(t *testing.T) {
+	_, _, err := Values().ToSql()
+	assert.Error(t, err)
+}
+
+func TestSelectBuilderIntersectExcept(t *testing.T) {
+	b := Select("id").From("a").
+		Union(Select("id").From("b")).
+		Intersect(Select("id").From("c")).
+		Except(Select("id").From("d"))
+	sql, _, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "(SELECT id FROM a) UNION (SELECT id FROM b) " +
+		"INTERSECT (SELECT id FROM c) EXCEPT (SELECT id FROM d)"
+	assert.Equal(t, expectedSql, sql)
+}
+
+func TestSelectBuilderForUpdateSkipLocked(t *testing.T) {
+	b := Select("id").From("jobs").
+		Where("state = 'queued'").
+		OrderBy("created_at").
+		Limit(5).
+		ForUpdate().
+		SkipLocked().
+		Suffix("/* worker */")
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT id FROM jobs WHERE state = 'queued' ORDER BY created_at LIMIT 5 " +
+		"FOR UPDATE SKIP LOCKED /* worker */"
+	assert.Equal(t, expectedSql, sql)
+	assert.Empty(t, args)
+}
+
+func TestSelectBuilderForShareNoWait(t *testing.T) {
+	sql, _, err := Select("id").From("jobs").ForShare().NoWait().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM jobs FOR SHARE NOWAIT", sql)
+}
+
+func TestSelectBuilderLockModes(t *testing.T) {
+	sql, _, err := Select("id").From("jobs").ForNoKeyUpdate().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM jobs FOR NO KEY UPDATE", sql)
+
+	sql, _, err = Select("id").From("jobs").ForKeyShare().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM jobs FOR KEY SHARE", sql)
+
+	sql, _, err = Select("j.id").From("jobs j").Join("queues q ON q.id = j.queue_id").
+		ForNoKeyUpdate().Of("j").NoWait().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT j.id FROM jobs j JOIN queues q ON q.id = j.queue_id FOR NO KEY UPDATE OF j NOWAIT", sql)
+
+	sql, _, err = Select("j.id").From("jobs j").Join("queues q ON q.id = j.queue_id").
+		ForKeyShare().Of("j", "q").SkipLocked().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT j.id FROM jobs j JOIN queues q ON q.id = j.queue_id FOR KEY SHARE OF j, q SKIP LOCKED", sql)
+}
+
+func TestSelectBuilderOrderByPosition(t *testing.T) {
+	b := Select("category", "brand", "COUNT(*)").From("products").
+		GroupBy("category", "brand").
+		OrderByPosition(1, 3)
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT category, brand, COUNT(*) FROM products GROUP BY category, brand ORDER BY 1, 3"
+	assert.Equal(t, expectedSql, sql)
+	assert.Empty(t, args)
+
+	_, _, err = Select("a", "b").From("t").OrderByPosition(3).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Select("a").From("t").OrderByPosition(0).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Select("*").From("t").OrderByPosition(3).ToSql()
+	assert.NoError(t, err)
+}
+
+func TestSelectBuilderDistinct(t *testing.T) {
+	sql, _, err := Select("a").From("t").Distinct().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT DISTINCT a FROM t", sql)
+
+	sql, _, err = Select("a", "b", "c").From("t").DistinctOn("a", "b").OrderBy("a", "b", "c").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT DISTINCT ON (a, b) a, b, c FROM t ORDER BY a, b, c", sql)
+
+	sql, _, err = Select("a").From("t").Distinct().DistinctOn("a").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT DISTINCT ON (a) a FROM t", sql)
+
+	sql, _, err = Select("a").From("t").DistinctOn("a").Distinct().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT DISTINCT a FROM t", sql)
+}
+
+func TestSelectBuilderPrewhere(t *testing.T) {
+	b := Select("url", "count()").From("hits").
+		Where(Eq{"user_agent": "bot"}).
+		Prewhere(Eq{"event_date": "2022-08-01"}).
+		Prewhere("counter_id = ?", 34).
+		GroupBy("url")
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT url, count() FROM hits PREWHERE event_date = ? AND counter_id = ? " +
+		"WHERE user_agent = ? GROUP BY url"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{"2022-08-01", 34, "bot"}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestSelectBuilderSettings(t *testing.T) {
+	b := Select("url").From("hits").
+		Where(Eq{"counter_id": 34}).
+		Limit(10).
+		Settings(map[string]interface{}{"max_threads": 8, "max_block_size": 65536})
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT url FROM hits WHERE counter_id = ? LIMIT 10 " +
+		"SETTINGS max_block_size = ?, max_threads = ?"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{34, 65536, 8}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestSelectBuilderEmptyParts(t *testing.T) {
+	b := Select().
+		Column(Empty()).
+		Column("a").
+		Column(Empty()).
+		Column("b").
+		From("t").
+		JoinClause(Empty()).
+		Where(Empty()).
+		Where(Or{Empty()}).
+		Where(Eq{"c": 1}).
+		Having(Empty())
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a, b FROM t WHERE c = ?", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, _, err = Select("a").From("t").Where(Empty()).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t", sql)
+
+	_, _, err = Select().Column(Empty()).From("t").ToSql()
+	assert.ErrorIs(t, err, ErrNoColumns)
+
+	_, _, err = Insert("t").Columns("a", "b").Values(1, Empty()).ToSql()
+	assert.ErrorIs(t, err, ErrNoValues)
+
+	_, _, err = Update("t").Set("a", Empty()).ToSql()
+	assert.ErrorIs(t, err, ErrNoValues)
+}
+
+func TestSelectBuilderSchemaQualifiedFunction(t *testing.T) {
+	b := Select().
+		Column(Alias(Expr("ext.similarity(name, ?)", "bob"), "score")).
+		From("public.users").
+		Where(Expr("ext.is_active(id, ?)", true))
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT (ext.similarity(name, ?)) AS score FROM public.users WHERE ext.is_active(id, ?)"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{"bob", true}, args)
+}
+
+func TestSelectBuilderRunContext(t *testing.T) {
+	db := &DBStub{row: []interface{}{7}}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "select")
+	b := Select("a").From("t").Where(Eq{"b": 1}).RunWith(db)
+
+	_, err := b.QueryContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, ctx, db.LastContext)
+	assert.Equal(t, "SELECT a FROM t WHERE b = ?", db.LastQuerySql)
+	assert.Equal(t, []interface{}{1}, db.LastQueryArgs)
+
+	var a int
+	assert.NoError(t, b.QueryRowContext(ctx).Scan(&a))
+	assert.Equal(t, 7, a)
+	assert.Equal(t, "SELECT a FROM t WHERE b = ?", db.LastQueryRowSql)
+}
+
+func TestSelectBuilderDistinctIf(t *testing.T) {
+	sql, _, err := Select("a").DistinctIf(true).From("t").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT DISTINCT a FROM t", sql)
+
+	sql, _, err = Select("a").DistinctIf(false).From("t").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t", sql)
+}
+
+func TestSelectBuilderScan(t *testing.T) {
+	db := &DBStub{row: []interface{}{42, "bob"}}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "scan")
+	b := Select("id", "name").From("users").Where(Eq{"email": "bob@example.com"}).RunWith(db)
+
+	var id int
+	var name string
+	err := b.ScanContext(ctx, &id, &name)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, id)
+	assert.Equal(t, "bob", name)
+	assert.Equal(t, ctx, db.LastContext)
+	assert.Equal(t, "SELECT id, name FROM users WHERE email = ?", db.LastQueryRowSql)
+	assert.Equal(t, []interface{}{"bob@example.com"}, db.LastQueryRowArgs)
+}
+
+func TestSelectBuilderScanError(t *testing.T) {
+	db := &DBStub{err: sql.ErrNoRows}
+	var id int
+	err := Select("id").From("users").RunWith(db).Scan(&id)
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	err = Select().RunWith(db).Scan(&id)
+	assert.Error(t, err)
+	assert.NotEqual(t, sql.ErrNoRows, err)
+}
+
+func TestSelectBuilderRun(t *testing.T) {
+	db := &DBStub{row: []interface{}{3}}
+	b := Select("a").From("t").Where(Eq{"b": 1}).RunWith(db)
+
+	_, err := b.Query()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t WHERE b = ?", db.LastQuerySql)
+	assert.Equal(t, []interface{}{1}, db.LastQueryArgs)
+
+	var a int
+	assert.NoError(t, b.Scan(&a))
+	assert.Equal(t, 3, a)
+	assert.Equal(t, "SELECT a FROM t WHERE b = ?", db.LastQueryRowSql)
+}
+
+func TestSelectBuilderOrderBySpec(t *testing.T) {
+	whitelist := map[string]string{
+		"created": "created_at",
+		"name":    "u.name",
+	}
+
+	sql, _, err := Select("id").From("users u").OrderBySpec("-created, name,", whitelist, false).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users u ORDER BY created_at DESC, u.name ASC", sql)
+
+	sql, _, err = Select("id").From("users u").OrderBySpec("password,+name", whitelist, true).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users u ORDER BY u.name ASC", sql)
+
+	_, _, err = Select("id").From("users u").OrderBySpec("-password", whitelist, false).ToSql()
+	assert.EqualError(t, err, `unknown sort key "password"`)
+}
+
+func TestSelectBuilderFromSelect(t *testing.T) {
+	sub := Select("id", "total").From("orders").Where(Eq{"state": "paid"})
+	b := Select("o.id", "u.name").
+		FromSelect(sub, "o").
+		From("users u").
+		Where("u.id = o.user_id AND o.total > ?", 100).
+		PlaceholderFormat(Dollar)
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT o.id, u.name FROM (SELECT id, total FROM orders WHERE state = $1) AS o, users u " +
+		"WHERE u.id = o.user_id AND o.total > $2"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{"paid", 100}, args)
+}
+
+func TestSelectBuilderPaginate(t *testing.T) {
+	sql, _, err := Select("id").From("t").Paginate(1, 20).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t LIMIT 20 OFFSET 0", sql)
+
+	sql, _, err = Select("id").From("t").Paginate(3, 20).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t LIMIT 20 OFFSET 40", sql)
+
+	sql, _, err = Select("id").From("t").Paginate(0, 20).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t LIMIT 20 OFFSET 0", sql)
+
+	sql, _, err = Select("id").From("t").Paginate(math.MaxUint64, 2).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t LIMIT 2 OFFSET 18446744073709551615", sql)
+}
+
+func TestSelectBuilderForTable(t *testing.T) {
+	recent := Select("user_id").From("logins").Where(Gt{"at": 100})
+	b := Select("e.id", "u.name").
+		From("events_0 e").
+		Join("users u ON u.id = e.user_id").
+		Where(Eq{"e.kind": "click"}).
+		Where(Eq{"e.user_id": recent})
+
+	for _, table := range []string{"events_1 e", "events_2 e", "events_3 e"} {
+		sql, args, err := b.ForTable(table).Where("e.seen = ?", true).ToSql()
+		assert.NoError(t, err)
+
+		expectedSql := "SELECT e.id, u.name FROM " + table + " JOIN users u ON u.id = e.user_id " +
+			"WHERE e.kind = ? AND e.user_id IN (SELECT user_id FROM logins WHERE at > ?) AND e.seen = ?"
+		assert.Equal(t, expectedSql, sql)
+		assert.Equal(t, []interface{}{"click", 100, true}, args)
+	}
+
+	sql, _, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT e.id, u.name FROM events_0 e JOIN users u ON u.id = e.user_id "+
+		"WHERE e.kind = ? AND e.user_id IN (SELECT user_id FROM logins WHERE at > ?)", sql)
+
+	sql, _, err = Select("e.id").FromAs("events_0", "e").Quoting(ANSIQuotes).ForTable("events_1").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT "e"."id" FROM "events_1" AS "e"`, sql)
+}
+
+func TestSelectBuilderGroupByRollup(t *testing.T) {
+	sql, _, err := Select("region", "product", "SUM(amount)").From("sales").
+		GroupBy("year").
+		GroupByRollup("region", "product").
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT region, product, SUM(amount) FROM sales GROUP BY year, ROLLUP(region, product)", sql)
+
+	sql, _, err = Select("a", "b", "COUNT(*)").From("t").GroupByCube("a", "b").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a, b, COUNT(*) FROM t GROUP BY CUBE(a, b)", sql)
+}
+
+func TestSelectBuilderGroupBySets(t *testing.T) {
+	sql, _, err := Select("region", "product", "SUM(amount)").From("sales").
+		GroupBySets([]string{"region", "product"}, []string{"region"}).
+		Having("SUM(amount) > ?", 10).
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT region, product, SUM(amount) FROM sales " +
+		"GROUP BY GROUPING SETS ((region, product), (region)) HAVING SUM(amount) > ?"
+	assert.Equal(t, expectedSql, sql)
+}
+
+func TestSelectBuilderValidationErrors(t *testing.T) {
+	_, _, err := Select().From("t").OrderByPosition(2).ToSql()
+	assert.EqualError(t, err, "select statements must have at least one result column\n"+
+		"order by position 2 is out of range, query has 0 columns")
+}
+
+func TestSelectBuilderStrictArgs(t *testing.T) {
+	_, _, err := Select("*").From("t").Where(Expr("a = ? AND b = ?", 1)).StrictArgs(true).ToSql()
+	assert.EqualError(t, err, "query has 2 placeholders but 1 args")
+
+	_, _, err = Select("*").From("t").Where(Expr("a = ?", 1, 2)).StrictArgs(true).ToSql()
+	assert.EqualError(t, err, "query has 1 placeholders but 2 args")
+
+	sql, args, err := Select("*").From("t").
+		Where("data ?? 'key' AND a = ?", 1).
+		PlaceholderFormat(Dollar).
+		StrictArgs(true).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE data ? 'key' AND a = $1", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	_, _, err = Select("*").From("t").Where(Expr("a = ? AND b = ?", 1)).ToSql()
+	assert.NoError(t, err)
+}
+
+func TestSelectBuilderInto(t *testing.T) {
+	sql, args, err := Select("id", "name").Into("users_archive").From("users").Where(Eq{"deleted": true}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, name INTO users_archive FROM users WHERE deleted = ?", sql)
+	assert.Equal(t, []interface{}{true}, args)
+
+	sql, _, err = Select("*").IntoTemp("recent").From("events").Where("created_at > now() - interval '1 day'").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * INTO TEMP recent FROM events WHERE created_at > now() - interval '1 day'", sql)
+}
+
+func TestSelectBuilderFromAs(t *testing.T) {
+	sql, _, err := Select("u.name").FromAs("users", "u").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT u.name FROM users AS u", sql)
+
+	sql, args, err := Select("u.name", "o.total").
+		FromAs("users", "u").
+		FromAs("app.orders", "o").
+		Where("o.user_id = u.id AND o.total > ?", 10).
+		Quoting(ANSIQuotes).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT "u"."name", "o"."total" FROM "users" AS "u", "app"."orders" AS "o" WHERE o.user_id = u.id AND o.total > ?`, sql)
+	assert.Equal(t, []interface{}{10}, args)
+}
+
+func TestSelectBuilderClone(t *testing.T) {
+	base := Select("id").From("users").Where(Eq{"team": 1}).OrderBy("id")
+	active := base.Clone().Where(Eq{"active": true}).Column("name")
+	admins := base.Clone().Where(Eq{"role": "admin"})
+
+	sql, args, err := base.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE team = ? ORDER BY id", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, args, err = active.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, name FROM users WHERE team = ? AND active = ? ORDER BY id", sql)
+	assert.Equal(t, []interface{}{1, true}, args)
+
+	sql, args, err = admins.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE team = ? AND role = ? ORDER BY id", sql)
+	assert.Equal(t, []interface{}{1, "admin"}, args)
+}
+
+type accountRow struct {
+	ID    int64   `db:"id"`
+	Name  string  `db:"name"`
+	Email *string `db:"email"`
+	Score int
+	auditFields
+}
+
+func TestScanStructs(t *testing.T) {
+	email := "bob@example.com"
+	rows := &RowsStub{
+		columns: []string{"id", "name", "email", "created_by"},
+		rows: [][]interface{}{
+			{int64(1), "alice", (*string)(nil), "admin"},
+			{int64(2), "bob", &email, "signup"},
+		},
+	}
+
+	var accounts []accountRow
+	err := scanStructs(rows, &accounts)
+	assert.NoError(t, err)
+	assert.Equal(t, []accountRow{
+		{ID: 1, Name: "alice", auditFields: auditFields{CreatedBy: "admin"}},
+		{ID: 2, Name: "bob", Email: &email, auditFields: auditFields{CreatedBy: "signup"}},
+	}, accounts)
+
+	rows = &RowsStub{columns: []string{"id", "name"}, rows: [][]interface{}{{int64(3), "carol"}}}
+	var ptrs []*accountRow
+	err = scanStructs(rows, &ptrs)
+	assert.NoError(t, err)
+	if assert.Len(t, ptrs, 1) {
+		assert.Equal(t, "carol", ptrs[0].Name)
+	}
+}
+
+func TestScanStructsErrors(t *testing.T) {
+	rows := &RowsStub{columns: []string{"id", "nickname"}, rows: [][]interface{}{{int64(1), "al"}}}
+	var accounts []accountRow
+	err := scanStructs(rows, &accounts)
+	assert.EqualError(t, err, `column "nickname" has no matching field in bsql.accountRow`)
+
+	rows = &RowsStub{columns: []string{"id", "score"}, rows: [][]interface{}{{int64(1), 5}}}
+	err = scanStructs(rows, &accounts)
+	assert.EqualError(t, err, `column "score" has no matching field in bsql.accountRow`)
+
+	db := &DBStub{}
+	err = Select("id").From("accounts").RunWith(db).QueryStructs(context.Background(), accounts)
+	assert.EqualError(t, err, "cannot scan into []bsql.accountRow; expected a pointer to a slice of structs")
+	assert.Empty(t, db.LastQuerySql)
+
+	db = &DBStub{err: StubError}
+	err = Select("id").From("accounts").RunWith(db).QueryStructs(context.Background(), &accounts)
+	assert.Equal(t, StubError, err)
+	assert.Equal(t, "SELECT id FROM accounts", db.LastQuerySql)
+}
+
+func TestSelectBuilderWhereIf(t *testing.T) {
+	build := func(name string, sorted bool, limit uint64) *SelectBuilder {
+		return Select("id").From("users").
+			WhereIf(name != "", Eq{"name": name}).
+			OrderByIf(sorted, "name").
+			LimitIf(limit > 0, limit)
+	}
+
+	sql, args, err := build("", false, 0).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users", sql)
+	assert.Empty(t, args)
+
+	sql, args, err = build("bob", true, 5).ToSql()
+	assert.NoError(t, err)
+	expectedSql, expectedArgs, _ := Select("id").From("users").
+		Where(Eq{"name": "bob"}).OrderBy("name").Limit(5).ToSql()
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestSelectBuilderLimitZero(t *testing.T) {
+	b := Select("1").From("users").Where(Eq{"email": "a@b.c"})
+
+	sql, _, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT 1 FROM users WHERE email = ?", sql)
+
+	sql, _, err = b.Limit(0).Offset(0).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT 1 FROM users WHERE email = ? LIMIT 0 OFFSET 0", sql)
+
+	sql, _, err = b.ClearLimit().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT 1 FROM users WHERE email = ? OFFSET 0", sql)
+
+	sql, _, err = b.ClearOffset().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT 1 FROM users WHERE email = ?", sql)
+}
+
+func TestSelectBuilderColumnArgs(t *testing.T) {
+	sql, args, err := Select("id").
+		Column("price * ? AS total", 1.2).
+		Column(Expr("COALESCE(discount, ?) AS discount", 0)).
+		From("items i").
+		Join("shops s ON s.id = i.shop_id AND s.region = ?", "eu").
+		Where(Eq{"i.active": true}).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT id, price * $1 AS total, COALESCE(discount, $2) AS discount FROM items i " +
+		"JOIN shops s ON s.id = i.shop_id AND s.region = $3 WHERE i.active = $4"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{1.2, 0, "eu", true}, args)
+}
+
+func TestSelectBuilderToCountSql(t *testing.T) {
+	b := Select("id", "name").From("users u").
+		Join("teams t ON t.id = u.team_id AND t.active = ?", true).
+		Where(Eq{"u.role": "admin"}).
+		OrderBy("name").
+		Limit(20).
+		Offset(40).
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := b.ToCountSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM users u JOIN teams t ON t.id = u.team_id AND t.active = $1 WHERE u.role = $2", sql)
+	assert.Equal(t, []interface{}{true, "admin"}, args)
+
+	sql, _, err = b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, name FROM users u JOIN teams t ON t.id = u.team_id AND t.active = $1 "+
+		"WHERE u.role = $2 ORDER BY name LIMIT 20 OFFSET 40", sql)
+}
+
+func TestSelectBuilderToCountSqlGrouped(t *testing.T) {
+	b := Select("team_id").Column("COUNT(*) AS n").From("users").
+		Where(Eq{"active": true}).
+		GroupBy("team_id").
+		Having("COUNT(*) > ?", 5).
+		OrderBy("n DESC").
+		Limit(10).
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := b.ToCountSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM (SELECT team_id, COUNT(*) AS n FROM users WHERE active = $1 "+
+		"GROUP BY team_id HAVING COUNT(*) > $2) AS _count", sql)
+	assert.Equal(t, []interface{}{true, 5}, args)
+
+	sql, args, err = Select("id").From("users").Where(Eq{"active": true}).Suffix("FETCH FIRST ? ROWS ONLY", 100).
+		PlaceholderFormat(Dollar).
+		ToCountSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM (SELECT id FROM users WHERE active = $1 FETCH FIRST $2 ROWS ONLY) AS _count", sql)
+	assert.Equal(t, []interface{}{true, 100}, args)
+}
+
+func TestSelectBuilderTableSample(t *testing.T) {
+	sql, args, err := Select("avg(p.amount)").
+		From("payments p").
+		TableSample("BERNOULLI", 2.5).
+		Join("users u ON u.id = p.user_id AND u.country = ?", "de").
+		Where(Gt{"p.created_at": "2024-01-01"}).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT avg(p.amount) FROM payments p TABLESAMPLE BERNOULLI ($1) " +
+		"JOIN users u ON u.id = p.user_id AND u.country = $2 WHERE p.created_at > $3"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{2.5, "de", "2024-01-01"}, args)
+
+	_, _, err = Select("*").TableSample("SYSTEM", 1).ToSql()
+	assert.Error(t, err)
+}
+
+func TestSelectBuilderHavingComparisons(t *testing.T) {
+	minOrders := 0
+	sql, args, err := Select("user_id").Column("COUNT(*)").From("orders").
+		Where(Eq{"status": "paid"}).
+		GroupBy("user_id").
+		Having(Gt{"COUNT(*)": 5}).
+		Having(LtOrEq{"SUM(total)": 1000}).
+		HavingIf(minOrders > 0, GtOrEq{"COUNT(*)": minOrders}).
+		HavingIf(true, Eq{"MAX(country)": "de"}).
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT user_id, COUNT(*) FROM orders WHERE status = ? GROUP BY user_id " +
+		"HAVING COUNT(*) > ? AND SUM(total) <= ? AND MAX(country) = ?"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{"paid", 5, 1000, "de"}, args)
+}
+
+func TestSelectBuilderPrefixSuffixArgs(t *testing.T) {
+	sql, args, err := Select("id").
+		Prefix("/*+ MAX_EXECUTION_TIME(?) */", 1000).
+		From("jobs").
+		Where(Eq{"state": "queued"}).
+		Suffix("FOR UPDATE SKIP LOCKED LIMIT ?", 10).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "/*+ MAX_EXECUTION_TIME(?) */ SELECT id FROM jobs WHERE state = ? FOR UPDATE SKIP LOCKED LIMIT ?", sql)
+	assert.Equal(t, []interface{}{1000, "queued", 10}, args)
+}
+
+func TestSelectBuilderSubqueryColumn(t *testing.T) {
+	orders := Select("COUNT(*)").From("orders o").Where("o.uid = u.id AND o.status = ?", "paid")
+	sql, args, err := Select("u.id").
+		Column(Alias(orders, "order_count")).
+		From("users u").
+		Join("teams t ON t.id = u.team_id AND t.kind = ?", "sales").
+		Where(Eq{"u.active": true}).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT u.id, (SELECT COUNT(*) FROM orders o WHERE o.uid = u.id AND o.status = $1) AS order_count "+
+		"FROM users u JOIN teams t ON t.id = u.team_id AND t.kind = $2 WHERE u.active = $3", sql)
+	assert.Equal(t, []interface{}{"paid", "sales", true}, args)
+}
+
+func TestSelectBuilderGroupByExpr(t *testing.T) {
+	sql, args, err := Select().
+		Column("COUNT(*)").
+		From("orders").
+		Where(Eq{"status": "paid"}).
+		GroupByExpr("width_bucket(amount, ?, ?, ?)", 0, 1000, 10).
+		Having("COUNT(*) > ?", 5).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM orders WHERE status = $1 "+
+		"GROUP BY width_bucket(amount, $2, $3, $4) HAVING COUNT(*) > $5", sql)
+	assert.Equal(t, []interface{}{"paid", 0, 1000, 10, 5}, args)
+
+	// A selected expression with args is grouped by position, see GroupByExpr.
+	sql, args, err = Select().
+		Column("date_trunc(?, created_at) AS day", "day").
+		Column("COUNT(*)").
+		From("events").
+		GroupBy("1").
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT date_trunc($1, created_at) AS day, COUNT(*) FROM events GROUP BY 1", sql)
+	assert.Equal(t, []interface{}{"day"}, args)
+}
+
+func TestSelectBuilderJoinOn(t *testing.T) {
+	sql, args, err := Select("u.name", "o.total").
+		From("users u").
+		LeftJoinOn("orders o", Eq{"o.uid": Expr("u.id")}).
+		JoinOn("teams t", And{Eq{"t.id": Expr("u.team_id")}, Eq{"t.kind": "sales"}, Gt{"t.size": 3}}).
+		Where(Eq{"u.active": true}).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT u.name, o.total FROM users u LEFT JOIN orders o ON o.uid = u.id "+
+		"JOIN teams t ON (t.id = u.team_id AND t.kind = $1 AND t.size > $2) WHERE u.active = $3", sql)
+	assert.Equal(t, []interface{}{"sales", 3, true}, args)
+
+	sql, args, err = Select("*").From("users u").JoinOn("teams t", And{}).LeftJoinOn("orders o", nil).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users u JOIN teams t ON true LEFT JOIN orders o ON true", sql)
+	assert.Empty(t, args)
+}
+
+func TestSelectBuilderResetColumns(t *testing.T) {
+	b := Select("id").Column("similarity(name, ?) AS score", "bob").From("users").Where(Eq{"active": true})
+
+	sql, args, err := b.Clone().ResetColumns("id", "name").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, name FROM users WHERE active = ?", sql)
+	assert.Equal(t, []interface{}{true}, args)
+
+	sql, args, err = b.RemoveColumns().Column(Count("*", "")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM users WHERE active = ?", sql)
+	assert.Equal(t, []interface{}{true}, args)
+
+	_, _, err = b.RemoveColumns().ToSql()
+	assert.ErrorIs(t, err, ErrNoColumns)
+}
@@ -1,8 +1,172 @@
 package bsql
 
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+)
+
 // StatementBuilderType is the type of StatementBuilder.
 type StatementBuilderType struct {
 	placeholderFormat PlaceholderFormat
+	placeholderOffset int
+	runner            BaseRunner
+	tagCaller         bool
+	strictArgs        bool
+	preEvalValuers    bool
+	normalizeNils     bool
+	boolAsInt         bool
+	standardLimit     bool
+	preferAnyArray    bool
+	quoting           QuoteStyle
+	flavor            flavor
+}
+
+// checkArgRefs reports an ArgRef in sql that refers past args.
+func checkArgRefs(sql string, args []interface{}) error {
+	if n := maxArgRef(sql); n > len(args) {
+		return buildErrorf(CodeArgMismatch, "ArgRef(%d) refers past the %d args of the query", n, len(args))
+	}
+	return nil
+}
+
+// finalize applies the statement-wide options to the SQL and args of a
+// top-level statement.
+func (b StatementBuilderType) finalize(sql string, args []interface{}) (string, []interface{}, error) {
+	if b.strictArgs {
+		if n := countPlaceholders(sql); n != len(args) {
+			return "", nil, buildErrorf(CodeArgMismatch, "query has %d placeholders but %d args", n, len(args))
+		}
+	}
+	if err := checkArgRefs(sql, args); err != nil {
+		return "", nil, err
+	}
+	if b.preEvalValuers {
+		var err error
+		if args, err = evalValuers(args); err != nil {
+			return "", nil, err
+		}
+	}
+	if b.normalizeNils {
+		args = normalizeNils(args)
+	}
+	if b.boolAsInt {
+		args = boolsAsInts(args)
+	}
+	if b.tagCaller {
+		tag, err := callerTag()
+		if err != nil {
+			return "", nil, err
+		}
+		sql += " " + tag
+	}
+	sql, err := b.format().ReplacePlaceholders(sql)
+	if err != nil {
+		return "", nil, err
+	}
+	return sql, args, nil
+}
+
+// checkPreferAnyArray reports PreferAnyArray set outside the Postgres
+// dialect.
+func (b StatementBuilderType) checkPreferAnyArray() error {
+	if b.preferAnyArray && b.flavor != flavorPostgres {
+		return buildErrorf(CodeInvalidClause, "PreferAnyArray requires the Postgres dialect")
+	}
+	return nil
+}
+
+// predicates returns the WHERE or HAVING parts to render, with Eq and NotEq
+// slices compared to an array arg when PreferAnyArray is set.
+func (b StatementBuilderType) predicates(parts []Sqlizer) []Sqlizer {
+	if !b.preferAnyArray {
+		return parts
+	}
+	return anyArrayConj(parts)
+}
+
+// evalValuers returns args with driver.Valuer args replaced by their value.
+// A nil pointer Valuer is replaced by nil, as database/sql does.
+func evalValuers(args []interface{}) ([]interface{}, error) {
+	var evaluated []interface{}
+	for i, arg := range args {
+		valuer, ok := arg.(driver.Valuer)
+		if !ok {
+			continue
+		}
+		if evaluated == nil {
+			evaluated = append([]interface{}(nil), args...)
+		}
+		if rv := reflect.ValueOf(arg); rv.Kind() == reflect.Ptr && rv.IsNil() {
+			evaluated[i] = nil
+			continue
+		}
+		val, err := valuer.Value()
+		if err != nil {
+			return nil, fmt.Errorf("arg %d: %w", i+1, err)
+		}
+		evaluated[i] = val
+	}
+	if evaluated == nil {
+		return args, nil
+	}
+	return evaluated, nil
+}
+
+// normalizeNils returns args with nil pointer args replaced by nil.
+// driver.Valuer args are kept, as their Value method may handle nil.
+func normalizeNils(args []interface{}) []interface{} {
+	var normalized []interface{}
+	for i, arg := range args {
+		if _, ok := arg.(driver.Valuer); ok {
+			continue
+		}
+		if rv := reflect.ValueOf(arg); rv.Kind() != reflect.Ptr || !rv.IsNil() {
+			continue
+		}
+		if normalized == nil {
+			normalized = append([]interface{}(nil), args...)
+		}
+		normalized[i] = nil
+	}
+	if normalized == nil {
+		return args
+	}
+	return normalized
+}
+
+// boolsAsInts returns args with bool args replaced by int64 1 or 0.
+func boolsAsInts(args []interface{}) []interface{} {
+	var converted []interface{}
+	for i, arg := range args {
+		v, ok := arg.(bool)
+		if !ok {
+			continue
+		}
+		if converted == nil {
+			converted = append([]interface{}(nil), args...)
+		}
+		converted[i] = int64(0)
+		if v {
+			converted[i] = int64(1)
+		}
+	}
+	if converted == nil {
+		return args
+	}
+	return converted
+}
+
+// format returns the PlaceholderFormat of the statement, which is Question
+// if none was set, numbering from the placeholder offset if it has one.
+func (b StatementBuilderType) format() PlaceholderFormat {
+	if b.placeholderFormat == nil {
+		return Question
+	}
+	if f, ok := b.placeholderFormat.(offsetFormat); ok && b.placeholderOffset > 0 {
+		return f.withOffset(b.placeholderOffset)
+	}
+	return b.placeholderFormat
 }
 
 // Select returns a SelectBuilder for this StatementBuilder.
@@ -20,6 +184,13 @@ func (b StatementBuilderType) Update(table string) *UpdateBuilder {
 	return NewUpdateBuilder(b).Table(table)
 }
 
+// UpdateFromValues returns a UpdateBuilder for this StatementBuilder that
+// updates many rows of table at once from a VALUES list, each row matched
+// by keyCol. See UpdateFromValues.
+func (b StatementBuilderType) UpdateFromValues(table string, keyCol string, rows [][]interface{}, cols []string) *UpdateBuilder {
+	return NewUpdateBuilder(b).Table(table).fromValues(keyCol, rows, cols)
+}
+
 // Delete returns a DeleteBuilder for this StatementBuilder.
 func (b StatementBuilderType) Delete(what ...string) *DeleteBuilder {
 	return NewDeleteBuilder(b).What(what...)
@@ -31,6 +202,76 @@ func (b StatementBuilderType) PlaceholderFormat(f PlaceholderFormat) StatementBu
 	return b
 }
 
+// PlaceholderOffset sets the PlaceholderOffset option for any child builders.
+func (b StatementBuilderType) PlaceholderOffset(n int) StatementBuilderType {
+	b.placeholderOffset = n
+	return b
+}
+
+// RunWith sets the RunWith field for any child builders.
+func (b StatementBuilderType) RunWith(runner BaseRunner) StatementBuilderType {
+	b.runner = wrapRunner(runner)
+	return b
+}
+
+// TagCaller sets the TagCaller option for any child builders.
+func (b StatementBuilderType) TagCaller(tag bool) StatementBuilderType {
+	b.tagCaller = tag
+	return b
+}
+
+// StrictArgs sets the StrictArgs option for any child builders.
+func (b StatementBuilderType) StrictArgs(strict bool) StatementBuilderType {
+	b.strictArgs = strict
+	return b
+}
+
+// PreEvalValuers sets the PreEvalValuers option for any child builders.
+func (b StatementBuilderType) PreEvalValuers(eval bool) StatementBuilderType {
+	b.preEvalValuers = eval
+	return b
+}
+
+// NormalizeNils sets the NormalizeNils option for any child builders.
+func (b StatementBuilderType) NormalizeNils(normalize bool) StatementBuilderType {
+	b.normalizeNils = normalize
+	return b
+}
+
+// BoolAsInt sets the BoolAsInt option for any child builders.
+func (b StatementBuilderType) BoolAsInt(asInt bool) StatementBuilderType {
+	b.boolAsInt = asInt
+	return b
+}
+
+// StandardLimitSyntax sets the StandardLimitSyntax option for any child
+// builders.
+func (b StatementBuilderType) StandardLimitSyntax(standard bool) StatementBuilderType {
+	b.standardLimit = standard
+	return b
+}
+
+// PreferAnyArray sets the PreferAnyArray option for any child builders.
+func (b StatementBuilderType) PreferAnyArray(prefer bool) StatementBuilderType {
+	b.preferAnyArray = prefer
+	return b
+}
+
+// Quoting sets the Quoting field for any child builders.
+func (b StatementBuilderType) Quoting(style QuoteStyle) StatementBuilderType {
+	b.quoting = style
+	return b
+}
+
+// Dialect sets the placeholder format, identifier quoting and SQL flavor for
+// any child builders.
+func (b StatementBuilderType) Dialect(d Dialect) StatementBuilderType {
+	b.placeholderFormat = d.PlaceholderFormat
+	b.quoting = d.Quoting
+	b.flavor = d.flavor
+	return b
+}
+
 // StatementBuilder is a basic statement builder, holds global configuration options
 // like placeholder format or SQL runner
 var StatementBuilder = StatementBuilderType{placeholderFormat: Question}
@@ -56,6 +297,22 @@ func Update(table string) *UpdateBuilder {
 	return StatementBuilder.Update(table)
 }
 
+// UpdateFromValues returns a new UpdateBuilder that updates many rows of
+// table at once from a VALUES list joined on keyCol, using the Postgres
+// idiom:
+//
+//   UpdateFromValues("items", "id", [][]interface{}{{1, 5}, {2, 7}}, []string{"id::int", "qty::int"})
+//   // UPDATE items AS t SET qty = v.qty::int FROM (VALUES (?,?),(?,?)) AS v(id, qty) WHERE t.id = v.id::int
+//
+// cols names the values of each row and must include keyCol; every other
+// column is set from its value. Postgres binds the values as text, so give
+// a "name::type" column the type of its table column to cast the values to
+// it. The table is aliased as t and must not have an alias of its own; refer
+// to it as t in further Where calls.
+func UpdateFromValues(table string, keyCol string, rows [][]interface{}, cols []string) *UpdateBuilder {
+	return StatementBuilder.UpdateFromValues(table, keyCol, rows, cols)
+}
+
 // Delete returns a new DeleteBuilder for given table names.
 //
 // See DeleteBuilder.Table.
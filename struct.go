@@ -0,0 +1,188 @@
+package bsql
+
+import (
+	"reflect"
+	"strings"
+)
+
+// structValues returns the columns and values of the exported fields of v,
+// which must be a struct or a pointer to one, in declaration order.
+//
+// Columns are named by the `db` tag. Fields without a tag name or tagged
+// `db:"-"` are skipped, as are zero-valued fields with the omitempty option,
+// e.g. `db:"name,omitempty"`. Embedded structs without a tag name have their
+// fields promoted.
+func structValues(v interface{}) (columns []string, values []interface{}, err error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, nil, buildErrorf(CodeInvalidArg, "cannot read columns from nil %T", v)
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, nil, buildErrorf(CodeInvalidArg, "cannot read columns from %T; expected a struct", v)
+	}
+
+	columns, values = appendStructValues(rv, columns, values)
+	return
+}
+
+func appendStructValues(rv reflect.Value, columns []string, values []interface{}) ([]string, []interface{}) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name, opts := parseDbTag(field.Tag.Get("db"))
+		if name == "-" {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if field.Anonymous && name == "" {
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				columns, values = appendStructValues(fv, columns, values)
+				continue
+			}
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" || opts == "omitempty" && fv.IsZero() {
+			continue
+		}
+
+		columns = append(columns, name)
+		values = append(values, fv.Interface())
+	}
+	return columns, values
+}
+
+func parseDbTag(tag string) (name, opts string) {
+	if i := strings.Index(tag, ","); i >= 0 {
+		return tag[:i], tag[i+1:]
+	}
+	return tag, ""
+}
+
+// rowsScanner is the part of *sql.Rows used by scanStructs.
+type rowsScanner interface {
+	Columns() ([]string, error)
+	Next() bool
+	Scan(dest ...interface{}) error
+	Err() error
+}
+
+// structSliceType returns the struct type of the elements of dest, which must
+// be a pointer to a slice of structs or of pointers to structs.
+func structSliceType(dest interface{}) (reflect.Type, error) {
+	rt := reflect.TypeOf(dest)
+	if rt == nil || rt.Kind() != reflect.Ptr || rt.Elem().Kind() != reflect.Slice {
+		return nil, buildErrorf(CodeInvalidArg, "cannot scan into %T; expected a pointer to a slice of structs", dest)
+	}
+	elem := rt.Elem().Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return nil, buildErrorf(CodeInvalidArg, "cannot scan into %T; expected a pointer to a slice of structs", dest)
+	}
+	return elem, nil
+}
+
+// structFields maps the column names of the fields of rt, named as by
+// structValues, to their field index.
+func structFields(rt reflect.Type, index []int, fields map[string][]int) map[string][]int {
+	if fields == nil {
+		fields = map[string][]int{}
+	}
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name, _ := parseDbTag(field.Tag.Get("db"))
+		if name == "-" {
+			continue
+		}
+
+		fieldIndex := append(index[:len(index):len(index)], i)
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				structFields(ft, fieldIndex, fields)
+				continue
+			}
+		}
+
+		if field.PkgPath != "" || name == "" {
+			continue
+		}
+		fields[name] = fieldIndex
+	}
+	return fields
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex, but allocates nil
+// embedded struct pointers on the way.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// scanStructs scans each row of rows into a new element appended to the
+// slice dest points to. Columns are matched to struct fields as by
+// structValues; a column without a matching field is an error.
+func scanStructs(rows rowsScanner, dest interface{}) error {
+	elemType, err := structSliceType(dest)
+	if err != nil {
+		return err
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	fields := structFields(elemType, nil, nil)
+	indexes := make([][]int, len(columns))
+	for i, column := range columns {
+		index, ok := fields[column]
+		if !ok {
+			return buildErrorf(CodeInvalidArg, "column %q has no matching field in %s", column, elemType)
+		}
+		indexes[i] = index
+	}
+
+	slice := reflect.ValueOf(dest).Elem()
+	byPointer := slice.Type().Elem().Kind() == reflect.Ptr
+	ptrs := make([]interface{}, len(columns))
+	for rows.Next() {
+		elem := reflect.New(elemType)
+		for i, index := range indexes {
+			ptrs[i] = fieldByIndex(elem.Elem(), index).Addr().Interface()
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return err
+		}
+		if byPointer {
+			slice.Set(reflect.Append(slice, elem))
+		} else {
+			slice.Set(reflect.Append(slice, elem.Elem()))
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,77 @@
+package bsql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// TxBeginner is the interface that wraps the BeginTx method of *sql.DB and
+// *sql.Conn.
+type TxBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// txRunner is a RunnerContext bound to a transaction.
+type txRunner interface {
+	RunnerContext
+	Commit() error
+	Rollback() error
+}
+
+type stdsqlTx struct {
+	*stdsqlCtxRunner
+	tx *sql.Tx
+}
+
+func (t stdsqlTx) Commit() error {
+	return t.tx.Commit()
+}
+
+func (t stdsqlTx) Rollback() error {
+	return t.tx.Rollback()
+}
+
+// WithTx begins a transaction on db and calls fn with a runner bound to it.
+// The transaction is committed if fn returns nil, and rolled back if fn
+// returns an error or panics, in which case the panic is resumed after the
+// rollback.
+//
+//   err := bsql.WithTx(ctx, db, func(tx bsql.RunnerContext) error {
+//       if _, err := Update("accounts").Set("balance", 0).Where(Eq{"id": 1}).RunWith(tx).ExecContext(ctx); err != nil {
+//           return err
+//       }
+//       _, err := Insert("audit").Columns("account_id").Values(1).RunWith(tx).ExecContext(ctx)
+//       return err
+//   })
+func WithTx(ctx context.Context, db TxBeginner, fn func(tx RunnerContext) error) error {
+	return runTx(func() (txRunner, error) {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		return stdsqlTx{&stdsqlCtxRunner{tx}, tx}, nil
+	}, fn)
+}
+
+func runTx(begin func() (txRunner, error), fn func(tx RunnerContext) error) (err error) {
+	tx, err := begin()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return errors.Join(err, rbErr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
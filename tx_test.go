@@ -0,0 +1,77 @@
+package bsql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TxStub is a transaction recording whether it was committed or rolled back.
+type TxStub struct {
+	DBStub
+	Committed  bool
+	RolledBack bool
+}
+
+func (tx *TxStub) Commit() error {
+	tx.Committed = true
+	return nil
+}
+
+func (tx *TxStub) Rollback() error {
+	tx.RolledBack = true
+	return nil
+}
+
+func beginStub(tx *TxStub) func() (txRunner, error) {
+	return func() (txRunner, error) {
+		return tx, nil
+	}
+}
+
+func TestRunTxCommit(t *testing.T) {
+	stub := &TxStub{}
+	err := runTx(beginStub(stub), func(tx RunnerContext) error {
+		_, err := Update("accounts").Set("balance", 0).Where(Eq{"id": 1}).RunWith(tx).ExecContext(context.Background())
+		return err
+	})
+	assert.NoError(t, err)
+	assert.True(t, stub.Committed)
+	assert.False(t, stub.RolledBack)
+	assert.Equal(t, "UPDATE accounts SET balance = ? WHERE id = ?", stub.LastExecSql)
+}
+
+func TestRunTxRollback(t *testing.T) {
+	stub := &TxStub{DBStub: DBStub{err: StubError}}
+	err := runTx(beginStub(stub), func(tx RunnerContext) error {
+		_, err := Delete("accounts").RunWith(tx).ExecContext(context.Background())
+		return err
+	})
+	assert.Equal(t, StubError, err)
+	assert.False(t, stub.Committed)
+	assert.True(t, stub.RolledBack)
+}
+
+func TestRunTxPanic(t *testing.T) {
+	stub := &TxStub{}
+	assert.PanicsWithValue(t, "boom", func() {
+		runTx(beginStub(stub), func(tx RunnerContext) error {
+			panic("boom")
+		})
+	})
+	assert.False(t, stub.Committed)
+	assert.True(t, stub.RolledBack)
+}
+
+func TestRunTxBeginError(t *testing.T) {
+	beginErr := errors.New("cannot begin")
+	called := false
+	err := runTx(func() (txRunner, error) { return nil, beginErr }, func(tx RunnerContext) error {
+		called = true
+		return nil
+	})
+	assert.Equal(t, beginErr, err)
+	assert.False(t, called)
+}
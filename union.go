@@ -0,0 +1,26 @@
+package bsql
+
+import (
+	"fmt"
+	"io"
+)
+
+// setOperation is a query combined with a select statement by UNION,
+// INTERSECT or EXCEPT.
+type setOperation struct {
+	op    string
+	query Sqlizer
+}
+
+func appendSetOperationsToSql(ops []setOperation, w io.Writer, args []interface{}) ([]interface{}, error) {
+	for _, o := range ops {
+		querySql, queryArgs, err := nestedToSql(o.query)
+		if err != nil {
+			return nil, err
+		}
+
+		fmt.Fprintf(w, " %s (%s)", o.op, querySql)
+		args = append(args, queryArgs...)
+	}
+	return args, nil
+}
@@ -2,8 +2,11 @@ package bsql
 
 import (
 	"bytes"
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
-	"sort"
+	"io"
 	"strconv"
 	"strings"
 )
@@ -13,6 +16,54 @@ type setClause struct {
 	value  interface{}
 }
 
+type setClauses []setClause
+
+// index returns the index of the clause setting column, or -1 if there is
+// none.
+func (sc setClauses) index(column string) int {
+	for i, c := range sc {
+		if c.column == column {
+			return i
+		}
+	}
+	return -1
+}
+
+func (sc setClauses) AppendToSql(w io.Writer, sep string, args []interface{}) ([]interface{}, error) {
+	for i, setClause := range sc {
+		var valSql string
+		switch typedVal := setClause.value.(type) {
+		case Sqlizer:
+			var valArgs []interface{}
+			var err error
+			valSql, valArgs, err = nestedToSql(typedVal)
+			if err != nil {
+				return nil, err
+			}
+			if valSql == "" {
+				return nil, buildErrorf(CodeNoValues, "set clause for %s has an empty value", setClause.column)
+			}
+			if _, ok := typedVal.(*SelectBuilder); ok {
+				valSql = "(" + valSql + ")"
+			}
+			args = append(args, valArgs...)
+		default:
+			valSql = "?"
+			args = append(args, typedVal)
+		}
+
+		if i > 0 {
+			if _, err := io.WriteString(w, sep); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s = %s", setClause.column, valSql); err != nil {
+			return nil, err
+		}
+	}
+	return args, nil
+}
+
 // Builder
 
 // UpdateBuilder builds SQL UPDATE statements.
@@ -21,10 +72,12 @@ type UpdateBuilder struct {
 
 	returning
 
+	comment    string
 	prefixes   exprs
 	table      string
+	tableAlias string
 	fromParts  []Sqlizer
-	setClauses []setClause
+	setClauses setClauses
 	whereParts []Sqlizer
 	orderBys   []string
 
@@ -34,6 +87,8 @@ type UpdateBuilder struct {
 	offsetValid bool
 
 	suffixes exprs
+
+	err error
 }
 
 // NewUpdateBuilder creates new instance of UpdateBuilder
@@ -41,6 +96,30 @@ func NewUpdateBuilder(b StatementBuilderType) *UpdateBuilder {
 	return &UpdateBuilder{StatementBuilderType: b}
 }
 
+// Clone returns a copy of b that can be changed without affecting b.
+//
+// See SelectBuilder.Clone.
+func (b *UpdateBuilder) Clone() *UpdateBuilder {
+	c := *b
+	c.returning = c.returning[:len(c.returning):len(c.returning)]
+	c.prefixes = c.prefixes[:len(c.prefixes):len(c.prefixes)]
+	c.fromParts = c.fromParts[:len(c.fromParts):len(c.fromParts)]
+	c.setClauses = c.setClauses[:len(c.setClauses):len(c.setClauses)]
+	c.whereParts = c.whereParts[:len(c.whereParts):len(c.whereParts)]
+	c.orderBys = c.orderBys[:len(c.orderBys):len(c.orderBys)]
+	c.suffixes = c.suffixes[:len(c.suffixes):len(c.suffixes)]
+	return &c
+}
+
+// ForTable returns a copy of the query updating table instead.
+//
+// See SelectBuilder.ForTable.
+func (b *UpdateBuilder) ForTable(table string) *UpdateBuilder {
+	c := b.Clone()
+	c.table = table
+	return c
+}
+
 // PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
 // query.
 func (b *UpdateBuilder) PlaceholderFormat(f PlaceholderFormat) *UpdateBuilder {
@@ -48,58 +127,175 @@ func (b *UpdateBuilder) PlaceholderFormat(f PlaceholderFormat) *UpdateBuilder {
 	return b
 }
 
-// ToSql builds the query into a SQL string and bound args.
-func (b *UpdateBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
+// TagCaller makes ToSql append a "/* at file.go:123 */" comment naming the
+// code outside of bsql that built the query, to trace queries seen in
+// database logs back to their source. ToSql fails if no such caller is found.
+func (b *UpdateBuilder) TagCaller(tag bool) *UpdateBuilder {
+	b.tagCaller = tag
+	return b
+}
+
+// PlaceholderOffset makes positional placeholders start at n+1.
+//
+// See SelectBuilder.PlaceholderOffset.
+func (b *UpdateBuilder) PlaceholderOffset(n int) *UpdateBuilder {
+	b.placeholderOffset = n
+	return b
+}
+
+// StrictArgs makes ToSql fail when the number of placeholders in the query
+// does not match the number of args.
+//
+// See SelectBuilder.StrictArgs.
+func (b *UpdateBuilder) StrictArgs(strict bool) *UpdateBuilder {
+	b.strictArgs = strict
+	return b
+}
+
+// PreEvalValuers makes ToSql replace driver.Valuer args by their value.
+//
+// See SelectBuilder.PreEvalValuers.
+func (b *UpdateBuilder) PreEvalValuers(eval bool) *UpdateBuilder {
+	b.preEvalValuers = eval
+	return b
+}
+
+// NormalizeNils makes ToSql replace nil pointer args by nil.
+//
+// See SelectBuilder.NormalizeNils.
+func (b *UpdateBuilder) NormalizeNils(normalize bool) *UpdateBuilder {
+	b.normalizeNils = normalize
+	return b
+}
+
+// PreferAnyArray makes Eq slices in Where render as "col = ANY(?)" with one
+// array arg.
+//
+// See SelectBuilder.PreferAnyArray.
+func (b *UpdateBuilder) PreferAnyArray(prefer bool) *UpdateBuilder {
+	b.preferAnyArray = prefer
+	return b
+}
+
+// BoolAsInt makes ToSql bind bool args as 1 and 0.
+//
+// See SelectBuilder.BoolAsInt.
+func (b *UpdateBuilder) BoolAsInt(asInt bool) *UpdateBuilder {
+	b.boolAsInt = asInt
+	return b
+}
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+//
+// *sql.DB and *sql.Tx are wrapped automatically.
+func (b *UpdateBuilder) RunWith(runner BaseRunner) *UpdateBuilder {
+	b.runner = wrapRunner(runner)
+	return b
+}
+
+// Exec builds and executes the query with the Runner set by RunWith.
+func (b *UpdateBuilder) Exec() (sql.Result, error) {
+	return exec(b.runner, b)
+}
+
+// Query builds and executes the query with the Runner set by RunWith.
+func (b *UpdateBuilder) Query() (*sql.Rows, error) {
+	return query(b.runner, b)
+}
+
+// QueryRow builds and executes the query with the Runner set by RunWith.
+// The Runner must implement QueryRower.
+func (b *UpdateBuilder) QueryRow() RowScanner {
+	return queryRow(b.runner, b)
+}
+
+// ExecContext builds and executes the query with the Runner set by RunWith.
+// The Runner must implement ExecerContext.
+func (b *UpdateBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
+	return execContext(ctx, b.runner, b)
+}
+
+// QueryContext builds and executes the query with the Runner set by RunWith.
+// The Runner must implement QueryerContext.
+func (b *UpdateBuilder) QueryContext(ctx context.Context) (*sql.Rows, error) {
+	return queryContext(ctx, b.runner, b)
+}
+
+// QueryRowContext builds and executes the query with the Runner set by
+// RunWith. The Runner must implement QueryRowerContext.
+func (b *UpdateBuilder) QueryRowContext(ctx context.Context) RowScanner {
+	return queryRowContext(ctx, b.runner, b)
+}
+
+// ExecReturning executes the query with the Runner set by RunWith and scans
+// the row produced by its RETURNING clause into dest. It returns
+// ErrNoReturning if Returning was not called.
+//
+// See InsertBuilder.ExecReturning.
+func (b *UpdateBuilder) ExecReturning(ctx context.Context, dest ...interface{}) error {
+	return b.returning.execReturning(ctx, b.runner, b, dest...)
+}
+
+// validate reports all structural problems of the query at once.
+func (b *UpdateBuilder) validate() error {
+	var errs []error
+	if b.err != nil {
+		errs = append(errs, b.err)
+	}
 	if len(b.table) == 0 {
-		err = fmt.Errorf("update statements must specify a table")
-		return
+		errs = append(errs, buildErrorf(CodeNoTable, "update statements must specify a table"))
 	}
 	if len(b.setClauses) == 0 {
-		err = fmt.Errorf("update statements must have at least one Set clause")
+		errs = append(errs, buildErrorf(CodeNoValues, "update statements must have at least one Set clause"))
+	}
+	if (b.limitValid || b.offsetValid) && b.flavor == flavorSQLServer {
+		errs = append(errs, buildErrorf(CodeInvalidClause, "update statements cannot have LIMIT or OFFSET under SQL Server"))
+	}
+	if err := b.checkPreferAnyArray(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (b *UpdateBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
+	if err = b.validate(); err != nil {
 		return
 	}
 
 	sql := &bytes.Buffer{}
 
 	if len(b.prefixes) > 0 {
-		args, _ = b.prefixes.AppendToSql(sql, " ", args)
+		args, err = b.prefixes.AppendToSql(sql, " ", args)
+		if err != nil {
+			return
+		}
 		sql.WriteString(" ")
 	}
 
 	sql.WriteString("UPDATE ")
+	appendComment(sql, b.comment)
 	sql.WriteString(b.table)
+	if b.tableAlias != "" {
+		sql.WriteString(" AS ")
+		sql.WriteString(b.tableAlias)
+	}
 
 	sql.WriteString(" SET ")
-	setSqls := make([]string, len(b.setClauses))
-	for i, setClause := range b.setClauses {
-		var valSql string
-		switch typedVal := setClause.value.(type) {
-		case Sqlizer:
-			var valArgs []interface{}
-			valSql, valArgs, err = typedVal.ToSql()
-			if err != nil {
-				return
-			}
-			args = append(args, valArgs...)
-		default:
-			valSql = "?"
-			args = append(args, typedVal)
-		}
-		setSqls[i] = fmt.Sprintf("%s = %s", setClause.column, valSql)
+	args, err = b.setClauses.AppendToSql(sql, ", ", args)
+	if err != nil {
+		return
 	}
-	sql.WriteString(strings.Join(setSqls, ", "))
 
 	if len(b.fromParts) > 0 {
-		sql.WriteString(" FROM ")
-		args, err = appendToSql(b.fromParts, sql, ", ", args)
+		args, err = appendClauseToSql(b.fromParts, sql, " FROM ", ", ", args)
 		if err != nil {
 			return
 		}
 	}
 
 	if len(b.whereParts) > 0 {
-		sql.WriteString(" WHERE ")
-		args, err = appendToSql(b.whereParts, sql, " AND ", args)
+		args, err = appendClauseToSql(b.predicates(b.whereParts), sql, " WHERE ", " AND ", args)
 		if err != nil {
 			return
 		}
@@ -110,7 +306,6 @@ func (b *UpdateBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 		sql.WriteString(strings.Join(b.orderBys, ", "))
 	}
 
-	// TODO: limit == 0 and offswt == 0 are valid. Need to go dbr way and implement offsetValid and limitValid
 	if b.limitValid {
 		sql.WriteString(" LIMIT ")
 		sql.WriteString(strconv.FormatUint(b.limit, 10))
@@ -130,15 +325,60 @@ func (b *UpdateBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 
 	if len(b.suffixes) > 0 {
 		sql.WriteString(" ")
-		args, _ = b.suffixes.AppendToSql(sql, " ", args)
+		args, err = b.suffixes.AppendToSql(sql, " ", args)
+		if err != nil {
+			return
+		}
 	}
 
-	sqlStr, err = b.placeholderFormat.ReplacePlaceholders(sql.String())
+	sqlStr, args, err = b.finalize(sql.String(), args)
 	return
 }
 
 // SQL methods
 
+// fromValues sets up b to update table, aliased as t, from rows joined on
+// keyCol, see StatementBuilderType.UpdateFromValues.
+func (b *UpdateBuilder) fromValues(keyCol string, rows [][]interface{}, cols []string) *UpdateBuilder {
+	names := make([]string, len(cols))
+	keyRef := ""
+	for i, col := range cols {
+		name, typ, _ := strings.Cut(col, "::")
+		names[i] = name
+		ref := "v." + name
+		if typ != "" {
+			ref += "::" + typ
+		}
+		if name == keyCol {
+			keyRef = ref
+			continue
+		}
+		b.Set(name, Expr(ref))
+	}
+	if keyRef == "" {
+		b.err = buildErrorf(CodeInvalidClause, "key column %s is not one of the values columns", keyCol)
+		return b
+	}
+	for _, row := range rows {
+		if len(row) != len(cols) {
+			b.err = buildErrorf(CodeArgMismatch, "values row has %d values, expected %d", len(row), len(cols))
+			return b
+		}
+	}
+
+	values := Expr(fmt.Sprintf("(?) AS v(%s)", strings.Join(names, ", ")), Values(rows...))
+	b.tableAlias = "t"
+	b.fromParts = append(b.fromParts, values)
+	return b.Where(fmt.Sprintf("t.%s = %s", keyCol, keyRef))
+}
+
+// Comment adds a "/* text */" comment right after the UPDATE keyword, e.g. to
+// tag queries for monitoring. Comment delimiters in text are neutralized.
+func (b *UpdateBuilder) Comment(text string) *UpdateBuilder {
+	b.comment = text
+	return b
+}
+
 // Prefix adds an expression to the beginning of the query
 func (b *UpdateBuilder) Prefix(sql string, args ...interface{}) *UpdateBuilder {
 	b.prefixes = append(b.prefixes, Expr(sql, args...))
@@ -152,27 +392,58 @@ func (b *UpdateBuilder) Table(table string) *UpdateBuilder {
 }
 
 // Set adds SET clauses to the query.
+//
+// A SelectBuilder value is rendered as a parenthesized subquery, e.g.
+// "total = (SELECT SUM(amount) FROM items WHERE ...)".
 func (b *UpdateBuilder) Set(column string, value interface{}) *UpdateBuilder {
 	b.setClauses = append(b.setClauses, setClause{column: column, value: value})
 	return b
 }
 
+// SetIncrement adds "column = column + ?" to the SET clauses of the query,
+// with by bound as the arg.
+func (b *UpdateBuilder) SetIncrement(column string, by interface{}) *UpdateBuilder {
+	return b.Set(column, Expr(column+" + ?", by))
+}
+
+// SetDecrement adds "column = column - ?" to the SET clauses of the query,
+// with by bound as the arg.
+func (b *UpdateBuilder) SetDecrement(column string, by interface{}) *UpdateBuilder {
+	return b.Set(column, Expr(column+" - ?", by))
+}
+
 // SetMap is a convenience method which calls .Set for each key/value pair in clauses.
 func (b *UpdateBuilder) SetMap(clauses map[string]interface{}) *UpdateBuilder {
-	keys := make([]string, len(clauses))
-	i := 0
-	for key := range clauses {
-		keys[i] = key
-		i++
+	for _, key := range sortedKeys(clauses) {
+		b = b.Set(key, clauses[key])
 	}
-	sort.Strings(keys)
-	for _, key := range keys {
-		val, _ := clauses[key]
-		b = b.Set(key, val)
+	return b
+}
+
+// SetMapAppend is like SetMap, but a column that already has a SET clause
+// gets the new value in place instead of a second assignment. Other columns
+// are added after the existing clauses in sorted order.
+func (b *UpdateBuilder) SetMapAppend(clauses map[string]interface{}) *UpdateBuilder {
+	// Copy before updating in place, the clauses may be shared with a Clone.
+	b.setClauses = append(setClauses(nil), b.setClauses...)
+	for _, key := range sortedKeys(clauses) {
+		i := b.setClauses.index(key)
+		if i < 0 {
+			b.setClauses = append(b.setClauses, setClause{column: key, value: clauses[key]})
+			continue
+		}
+		b.setClauses[i].value = clauses[key]
 	}
 	return b
 }
 
+// SetMapOmitNil is like SetMap, but skips the entries whose value is nil or a
+// nil pointer, leaving those columns unchanged. Zero values such as 0 or ""
+// are kept.
+func (b *UpdateBuilder) SetMapOmitNil(clauses map[string]interface{}) *UpdateBuilder {
+	return b.SetMap(withoutNils(clauses))
+}
+
 // Where adds WHERE expressions to the query.
 //
 // See SelectBuilder.Where for more information.
@@ -181,6 +452,15 @@ func (b *UpdateBuilder) Where(pred interface{}, args ...interface{}) *UpdateBuil
 	return b
 }
 
+// WhereIf adds a WHERE expression to the query when cond is true and is
+// a no-op otherwise.
+func (b *UpdateBuilder) WhereIf(cond bool, pred interface{}, args ...interface{}) *UpdateBuilder {
+	if !cond {
+		return b
+	}
+	return b.Where(pred, args...)
+}
+
 // From adds tables to FROM clause of the query.
 //
 // UPDATE ... FROM is an PostgreSQL specific extension
@@ -208,6 +488,15 @@ func (b *UpdateBuilder) OrderBy(orderBys ...string) *UpdateBuilder {
 	return b
 }
 
+// OrderByIf adds ORDER BY expressions to the query when cond is true and is
+// a no-op otherwise.
+func (b *UpdateBuilder) OrderByIf(cond bool, orderBys ...string) *UpdateBuilder {
+	if !cond {
+		return b
+	}
+	return b.OrderBy(orderBys...)
+}
+
 // Limit sets a LIMIT clause on the query.
 func (b *UpdateBuilder) Limit(limit uint64) *UpdateBuilder {
 	b.limit = limit
@@ -215,6 +504,15 @@ func (b *UpdateBuilder) Limit(limit uint64) *UpdateBuilder {
 	return b
 }
 
+// LimitIf adds a LIMIT clause to the query when cond is true and is
+// a no-op otherwise.
+func (b *UpdateBuilder) LimitIf(cond bool, limit uint64) *UpdateBuilder {
+	if !cond {
+		return b
+	}
+	return b.Limit(limit)
+}
+
 // Offset sets a OFFSET clause on the query.
 func (b *UpdateBuilder) Offset(offset uint64) *UpdateBuilder {
 	b.offset = offset
@@ -222,6 +520,21 @@ func (b *UpdateBuilder) Offset(offset uint64) *UpdateBuilder {
 	return b
 }
 
+// ClearLimit removes the LIMIT clause set by Limit. Unlike Limit(0), which
+// renders "LIMIT 0", it leaves the query without a LIMIT clause.
+func (b *UpdateBuilder) ClearLimit() *UpdateBuilder {
+	b.limit = 0
+	b.limitValid = false
+	return b
+}
+
+// ClearOffset removes the OFFSET clause set by Offset.
+func (b *UpdateBuilder) ClearOffset() *UpdateBuilder {
+	b.offset = 0
+	b.offsetValid = false
+	return b
+}
+
 // Returning adds columns to RETURNING clause of the query
 //
 // UPDATE ... RETURNING is PostgreSQL specific extension
@@ -230,6 +543,16 @@ func (b *UpdateBuilder) Returning(columns ...string) *UpdateBuilder {
 	return b
 }
 
+// ReturningAll sets a RETURNING * clause, returning all columns of the
+// affected rows. It replaces the columns added by Returning and
+// ReturningSelect, and is replaced by the ones added after it.
+//
+// UPDATE ... RETURNING is PostgreSQL specific extension
+func (b *UpdateBuilder) ReturningAll() *UpdateBuilder {
+	b.returning.ReturningAll()
+	return b
+}
+
 // ReturningSelect adds subquery to RETURNING clause of the query
 //
 // UPDATE ... RETURNING is PostgreSQL specific extension
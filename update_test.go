@@ -0,0 +1,274 @@
+package bsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateBuilderSetSubquery(t *testing.T) {
+	total := Select("SUM(amount)").From("items").
+		Where("items.order_id = orders.id").
+		Where(Eq{"items.state": "paid"})
+	b := Update("orders").
+		Set("total", total).
+		Set("updated_by", "job").
+		Where(Eq{"orders.id": 9}).
+		PlaceholderFormat(Dollar)
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "UPDATE orders SET " +
+		"total = (SELECT SUM(amount) FROM items WHERE items.order_id = orders.id AND items.state = $1), " +
+		"updated_by = $2 WHERE orders.id = $3"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{"paid", "job", 9}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestUpdateBuilderLimit(t *testing.T) {
+	sql, args, err := Update("t").Set("a", 1).Where(Eq{"b": 2}).OrderBy("id").Limit(100).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = ? WHERE b = ? ORDER BY id LIMIT 100", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+
+	sql, _, err = Update("t").Set("a", 1).Limit(0).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = ? LIMIT 0", sql)
+}
+
+func TestUpdateBuilderRunContext(t *testing.T) {
+	db := &DBStub{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "update")
+	b := Update("t").Set("a", 1).Where(Eq{"b": 2}).PlaceholderFormat(Dollar).RunWith(db)
+
+	_, err := b.ExecContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, ctx, db.LastContext)
+	assert.Equal(t, "UPDATE t SET a = $1 WHERE b = $2", db.LastExecSql)
+	assert.Equal(t, []interface{}{1, 2}, db.LastExecArgs)
+}
+
+func TestUpdateFromValues(t *testing.T) {
+	rows := [][]interface{}{{1, "a", 10}, {2, "b", 20}}
+	b := UpdateFromValues("items", "id", rows, []string{"id::bigint", "name", "qty::int"}).
+		Where("t.locked = ?", false).
+		PlaceholderFormat(Dollar)
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "UPDATE items AS t SET name = v.name, qty = v.qty::int " +
+		"FROM (VALUES ($1,$2,$3),($4,$5,$6)) AS v(id, name, qty) " +
+		"WHERE t.id = v.id::bigint AND t.locked = $7"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{1, "a", 10, 2, "b", 20, false}, args)
+
+	sql, _, err = b.ForTable("items_archive").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE items_archive AS t SET name = v.name, qty = v.qty::int "+
+		"FROM (VALUES ($1,$2,$3),($4,$5,$6)) AS v(id, name, qty) "+
+		"WHERE t.id = v.id::bigint AND t.locked = $7", sql)
+}
+
+func TestUpdateFromValuesErrors(t *testing.T) {
+	_, _, err := UpdateFromValues("items", "id", [][]interface{}{{"a"}}, []string{"name"}).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = UpdateFromValues("items", "id", [][]interface{}{{1}}, []string{"id", "name"}).ToSql()
+	assert.Error(t, err)
+
+	_, _, err = UpdateFromValues("items", "id", nil, []string{"id", "name"}).ToSql()
+	assert.Error(t, err)
+}
+
+func TestUpdateBuilderForTable(t *testing.T) {
+	sql, _, err := Update("events_0").Set("a", 1).ForTable("events_1").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE events_1 SET a = ?", sql)
+}
+
+func TestUpdateBuilderSetIncrement(t *testing.T) {
+	b := Update("posts").
+		Set("title", "hello").
+		SetIncrement("views", 1).
+		SetDecrement("credits", 5).
+		Where(Eq{"id": 7}).
+		PlaceholderFormat(Dollar)
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "UPDATE posts SET title = $1, views = views + $2, credits = credits - $3 WHERE id = $4"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{"hello", 1, 5, 7}, args)
+}
+
+func TestUpdateBuilderSkipLockedQueue(t *testing.T) {
+	next := Select("id").From("jobs").
+		Where(Eq{"status": "queued"}).
+		OrderBy("created").
+		Limit(10).
+		ForUpdate().
+		SkipLocked()
+	b := Update("jobs").
+		Set("status", "running").
+		Set("worker", "w1").
+		Where(Eq{"id": next}).
+		Returning("*").
+		PlaceholderFormat(Dollar)
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "UPDATE jobs SET status = $1, worker = $2 " +
+		"WHERE id IN (SELECT id FROM jobs WHERE status = $3 ORDER BY created LIMIT 10 FOR UPDATE SKIP LOCKED) " +
+		"RETURNING *"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{"running", "w1", "queued"}, args)
+}
+
+func TestUpdateBuilderFromSelect(t *testing.T) {
+	totals := Select("order_id", "SUM(amount) AS total").From("items").
+		Where(Eq{"refunded": false}).
+		GroupBy("order_id")
+	b := Update("orders").
+		Set("total", Expr("t.total")).
+		Set("updated_by", "sync").
+		FromSelect(totals, "t").
+		Where("orders.id = t.order_id AND orders.state = ?", "open").
+		PlaceholderFormat(Dollar)
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "UPDATE orders SET total = t.total, updated_by = $1 " +
+		"FROM (SELECT order_id, SUM(amount) AS total FROM items WHERE refunded = $2 GROUP BY order_id) AS t " +
+		"WHERE orders.id = t.order_id AND orders.state = $3"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{"sync", false, "open"}, args)
+}
+
+func TestUpdateBuilderFrom(t *testing.T) {
+	sql, _, err := Update("a").Set("x", Expr("b.x")).From("b").Where("a.bid = b.id").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE a SET x = b.x FROM b WHERE a.bid = b.id", sql)
+}
+
+func TestUpdateBuilderSetMapOmitNil(t *testing.T) {
+	name := "bob"
+	var nilEmail *string
+	sql, args, err := Update("users").SetMapOmitNil(map[string]interface{}{
+		"name":  &name,
+		"email": nilEmail,
+		"bio":   nil,
+		"age":   0,
+	}).Where(Eq{"id": 1}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET age = ?, name = ? WHERE id = ?", sql)
+	assert.Equal(t, []interface{}{0, &name, 1}, args)
+}
+
+func TestUpdateBuilderExecReturning(t *testing.T) {
+	db := &DBStub{row: []interface{}{int64(3)}}
+	var version int64
+	err := Update("docs").SetIncrement("version", 1).Where(Eq{"id": 9}).
+		Returning("version").PlaceholderFormat(Dollar).RunWith(db).
+		ExecReturning(context.Background(), &version)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), version)
+	assert.Equal(t, "UPDATE docs SET version = version + $1 WHERE id = $2 RETURNING version", db.LastQueryRowSql)
+	assert.Equal(t, []interface{}{1, 9}, db.LastQueryRowArgs)
+
+	err = Update("docs").Set("a", 1).RunWith(db).ExecReturning(context.Background(), &version)
+	assert.Equal(t, ErrNoReturning, err)
+}
+
+func TestUpdateBuilderValidationErrors(t *testing.T) {
+	_, _, err := Update("").Where(Eq{"id": 1}).ToSql()
+	assert.EqualError(t, err, "update statements must specify a table\n"+
+		"update statements must have at least one Set clause")
+}
+
+func TestUpdateBuilderPreEvalValuers(t *testing.T) {
+	var nilValuer *noteValuer
+	sql, args, err := StatementBuilder.PreEvalValuers(true).
+		Update("users").Set("status", statusValuer("inactive")).Set("note", nilValuer).
+		Where("id = ?", 1).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET status = ?, note = ? WHERE id = ?", sql)
+	assert.Equal(t, []interface{}{int64(0), nil, 1}, args)
+}
+
+type noteValuer struct{ s string }
+
+func (n *noteValuer) Value() (driver.Value, error) {
+	return n.s, nil
+}
+
+func TestUpdateBuilderWhereIf(t *testing.T) {
+	sql, args, err := Update("users").Set("a", 1).
+		WhereIf(false, "id = ?", 1).
+		WhereIf(true, "team = ?", 2).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET a = ? WHERE team = ?", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+}
+
+func TestUpdateBuilderClearLimit(t *testing.T) {
+	sql, _, err := Update("t").Set("a", 1).Limit(0).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = ? LIMIT 0", sql)
+
+	sql, _, err = Update("t").Set("a", 1).Limit(10).ClearLimit().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = ?", sql)
+}
+
+func TestUpdateBuilderPrefixSuffixArgs(t *testing.T) {
+	sql, args, err := Update("jobs").
+		Prefix("WITH stale AS (SELECT id FROM jobs WHERE heartbeat < ?)", "2020-01-01").
+		Set("state", "queued").
+		Where("id IN (SELECT id FROM stale)").
+		Suffix("RETURNING id, ?", "requeued").
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "WITH stale AS (SELECT id FROM jobs WHERE heartbeat < ?) UPDATE jobs SET state = ? WHERE id IN (SELECT id FROM stale) RETURNING id, ?", sql)
+	assert.Equal(t, []interface{}{"2020-01-01", "queued", "requeued"}, args)
+}
+
+func TestUpdateBuilderSetMapStable(t *testing.T) {
+	clauses := map[string]interface{}{"e": 5, "b": 2, "d": 4, "a": 1, "c": 3}
+	for i := 0; i < 20; i++ {
+		sql, args, err := Update("t").SetMap(clauses).ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, "UPDATE t SET a = ?, b = ?, c = ?, d = ?, e = ?", sql)
+		assert.Equal(t, []interface{}{1, 2, 3, 4, 5}, args)
+	}
+}
+
+func TestUpdateBuilderSetMapAppend(t *testing.T) {
+	base := Update("users").Set("name", "bob").Set("age", 30)
+	b := base.Clone().SetMapAppend(map[string]interface{}{"email": "bob@example.com", "age": 31}).Where(Eq{"id": 1})
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET name = ?, age = ?, email = ? WHERE id = ?", sql)
+	assert.Equal(t, []interface{}{"bob", 31, "bob@example.com", 1}, args)
+
+	_, args, err = base.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"bob", 30}, args)
+}
+
+func TestUpdateBuilderBoolAsInt(t *testing.T) {
+	b := Update("users").Set("active", true).SetMap(map[string]interface{}{"admin": false}).Where(Eq{"verified": true, "id": 1})
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET active = ?, admin = ? WHERE id = ? AND verified = ?", sql)
+	assert.Equal(t, []interface{}{true, false, 1, true}, args)
+
+	_, args, err = b.BoolAsInt(true).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{int64(1), int64(0), 1, int64(1)}, args)
+}
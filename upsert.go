@@ -0,0 +1,241 @@
+package bsql
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+type onConflict struct {
+	target    []string
+	doNothing bool
+	sets      setClauses
+}
+
+func (c *onConflict) AppendToSql(w io.Writer, args []interface{}) ([]interface{}, error) {
+	if !c.doNothing && len(c.sets) == 0 {
+		return nil, buildErrorf(CodeInvalidClause, "on conflict clause must specify DO NOTHING or DO UPDATE SET")
+	}
+
+	io.WriteString(w, " ON CONFLICT")
+	if len(c.target) > 0 {
+		io.WriteString(w, " (")
+		io.WriteString(w, strings.Join(c.target, ", "))
+		io.WriteString(w, ")")
+	}
+
+	if c.doNothing {
+		io.WriteString(w, " DO NOTHING")
+		return args, nil
+	}
+
+	io.WriteString(w, " DO UPDATE SET ")
+	return c.sets.AppendToSql(w, ", ", args)
+}
+
+// OnConflictBuilder builds the ON CONFLICT clause of an insert statement.
+//
+// It embeds the InsertBuilder it was created from, so the statement can be
+// continued (e.g. with Returning) or built directly.
+type OnConflictBuilder struct {
+	*InsertBuilder
+}
+
+// OnConflict adds an ON CONFLICT clause for the given conflict target columns
+// to the query. The action is set with DoNothing or DoUpdateSet.
+//
+// INSERT ... ON CONFLICT is PostgreSQL specific extension
+func (b *InsertBuilder) OnConflict(target ...string) *OnConflictBuilder {
+	b.onConflict = &onConflict{target: target}
+	return &OnConflictBuilder{b}
+}
+
+// DoNothing sets the conflict action to DO NOTHING.
+func (c *OnConflictBuilder) DoNothing() *OnConflictBuilder {
+	c.onConflict.doNothing = true
+	c.onConflict.sets = nil
+	return c
+}
+
+// DoUpdateSet adds DO UPDATE SET assignments for each key/value pair in
+// clauses, in sorted column order.
+func (c *OnConflictBuilder) DoUpdateSet(clauses map[string]interface{}) *OnConflictBuilder {
+	for _, column := range sortedKeys(clauses) {
+		c.doUpdateSet(column, clauses[column])
+	}
+	return c
+}
+
+// DoUpdateSetExpr adds a DO UPDATE SET assignment of an expression to column,
+// for example:
+//   DoUpdateSetExpr("qty", "t.qty + EXCLUDED.qty")
+func (c *OnConflictBuilder) DoUpdateSetExpr(column, sql string, args ...interface{}) *OnConflictBuilder {
+	return c.doUpdateSet(column, Expr(sql, args...))
+}
+
+func (c *OnConflictBuilder) doUpdateSet(column string, value interface{}) *OnConflictBuilder {
+	c.onConflict.doNothing = false
+	c.onConflict.sets = append(c.onConflict.sets, setClause{column: column, value: value})
+	return c
+}
+
+// OnDuplicateKeyUpdate adds ON DUPLICATE KEY UPDATE assignments for each
+// key/value pair in clauses, in sorted column order. Use Expr to refer to the
+// inserted values, e.g. Expr("VALUES(qty)").
+//
+// INSERT ... ON DUPLICATE KEY UPDATE is MySQL specific extension
+func (b *InsertBuilder) OnDuplicateKeyUpdate(clauses map[string]interface{}) *InsertBuilder {
+	for _, column := range sortedKeys(clauses) {
+		b.duplicateKeyUpdates = append(b.duplicateKeyUpdates, setClause{column: column, value: clauses[column]})
+	}
+	return b
+}
+
+// OnDuplicateKeyUpdateExpr adds an ON DUPLICATE KEY UPDATE assignment of an
+// expression to column, for example:
+//   OnDuplicateKeyUpdateExpr("qty", "qty + VALUES(qty)")
+//
+// INSERT ... ON DUPLICATE KEY UPDATE is MySQL specific extension
+func (b *InsertBuilder) OnDuplicateKeyUpdateExpr(column, sql string, args ...interface{}) *InsertBuilder {
+	b.duplicateKeyUpdates = append(b.duplicateKeyUpdates, setClause{column: column, value: Expr(sql, args...)})
+	return b
+}
+
+// AsUpsert makes the insert update the existing row instead when a row with
+// the same keyCols exists, setting its other columns to the inserted values.
+// The statement depends on the Dialect:
+//
+//   -- Postgres, SQLite and the default
+//   INSERT INTO t (id,name) VALUES (?,?) ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name
+//   -- MySQL
+//   INSERT INTO t (id,name) VALUES (?,?) ON DUPLICATE KEY UPDATE name = VALUES(name)
+//   -- SQL Server
+//   MERGE INTO t AS target USING (VALUES (@p1,@p2)) AS source (id,name)
+//   ON target.id = source.id
+//   WHEN MATCHED THEN UPDATE SET target.name = source.name
+//   WHEN NOT MATCHED THEN INSERT (id,name) VALUES (source.id,source.name);
+//
+// MySQL matches rows by the unique keys of the table rather than by keyCols.
+// keyCols must be insert columns. AsUpsert cannot be combined with
+// OnConflict, OnDuplicateKeyUpdate or Ignore, nor with Returning under SQL
+// Server.
+func (b *InsertBuilder) AsUpsert(keyCols []string) *InsertBuilder {
+	b.upsertKeys = append([]string{}, keyCols...)
+	return b
+}
+
+// upsertUpdateColumns returns the insert columns that are not upsert keys.
+func (b *InsertBuilder) upsertUpdateColumns() []string {
+	keys := make(map[string]bool, len(b.upsertKeys))
+	for _, key := range b.upsertKeys {
+		keys[key] = true
+	}
+	var columns []string
+	for _, column := range b.columns {
+		if !keys[column] {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// upsertClauses returns the ON CONFLICT or ON DUPLICATE KEY UPDATE clause
+// AsUpsert renders as for the dialect of b.
+func (b *InsertBuilder) upsertClauses() (*onConflict, setClauses) {
+	columns := b.upsertUpdateColumns()
+	if b.flavor == flavorMySQL {
+		if len(columns) == 0 {
+			// ON DUPLICATE KEY UPDATE needs an assignment; a no-op one
+			// keeps the existing row as DO NOTHING would.
+			columns = b.upsertKeys[:1]
+		}
+		var sets setClauses
+		for _, column := range columns {
+			quoted := b.quoting.Quote(column)
+			sets = append(sets, setClause{column: quoted, value: Expr("VALUES(" + quoted + ")")})
+		}
+		return nil, sets
+	}
+
+	c := &onConflict{doNothing: len(columns) == 0}
+	for _, key := range b.upsertKeys {
+		c.target = append(c.target, b.quoting.Quote(key))
+	}
+	for _, column := range columns {
+		quoted := b.quoting.Quote(column)
+		c.sets = append(c.sets, setClause{column: quoted, value: Expr("EXCLUDED." + quoted)})
+	}
+	return c, nil
+}
+
+// mergeSql builds the SQL Server MERGE statement of an AsUpsert insert.
+func (b *InsertBuilder) mergeSql() (sqlStr string, args []interface{}, err error) {
+	sql := &bytes.Buffer{}
+
+	if len(b.prefixes) > 0 {
+		args, err = b.prefixes.AppendToSql(sql, " ", args)
+		if err != nil {
+			return
+		}
+		sql.WriteString(" ")
+	}
+
+	sql.WriteString("MERGE ")
+	appendComment(sql, b.comment)
+	sql.WriteString("INTO ")
+	sql.WriteString(b.quoting.Quote(b.into))
+	sql.WriteString(" AS target USING (")
+	if b.iselect != nil {
+		args, err = b.appendSelectToSQL(sql, args)
+	} else {
+		args, err = b.appendValuesToSQL(sql, args)
+	}
+	if err != nil {
+		return
+	}
+
+	columns := make([]string, len(b.columns))
+	sources := make([]string, len(b.columns))
+	for i, column := range b.columns {
+		columns[i] = b.quoting.Quote(column)
+		sources[i] = "source." + columns[i]
+	}
+	sql.WriteString(") AS source (")
+	sql.WriteString(strings.Join(columns, ","))
+	sql.WriteString(") ON ")
+	for i, key := range b.upsertKeys {
+		if i > 0 {
+			sql.WriteString(" AND ")
+		}
+		key = b.quoting.Quote(key)
+		sql.WriteString("target." + key + " = source." + key)
+	}
+
+	if updates := b.upsertUpdateColumns(); len(updates) > 0 {
+		sql.WriteString(" WHEN MATCHED THEN UPDATE SET ")
+		for i, column := range updates {
+			if i > 0 {
+				sql.WriteString(", ")
+			}
+			column = b.quoting.Quote(column)
+			sql.WriteString("target." + column + " = source." + column)
+		}
+	}
+	sql.WriteString(" WHEN NOT MATCHED THEN INSERT (")
+	sql.WriteString(strings.Join(columns, ","))
+	sql.WriteString(") VALUES (")
+	sql.WriteString(strings.Join(sources, ","))
+	sql.WriteString(")")
+
+	if len(b.suffixes) > 0 {
+		sql.WriteString(" ")
+		args, err = b.suffixes.AppendToSql(sql, " ", args)
+		if err != nil {
+			return
+		}
+	}
+	// SQL Server requires MERGE statements to be terminated.
+	sql.WriteString(";")
+
+	return b.finalize(sql.String(), args)
+}
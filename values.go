@@ -0,0 +1,88 @@
+package bsql
+
+import (
+	"bytes"
+	"io"
+)
+
+// valuesList is a list of rows rendered as a VALUES clause.
+type valuesList [][]interface{}
+
+// Values builds a standalone VALUES list from rows, for use as a set
+// operation operand or a derived table.
+//
+//   Select("id", "name").From("users").UnionAll(Values([]interface{}{0, "all"}))
+//   // (SELECT id, name FROM users) UNION ALL (VALUES (?,?))
+func Values(rows ...[]interface{}) Sqlizer {
+	return valuesList(rows)
+}
+
+func (v valuesList) ToSql() (sql string, args []interface{}, err error) {
+	buf := &bytes.Buffer{}
+	args, err = v.AppendToSql(buf, nil)
+	if err != nil {
+		return
+	}
+	sql = buf.String()
+	return
+}
+
+// AppendToSql writes "VALUES (...),(...)" to w. Sqlizer values are
+// inlined, with subqueries in parentheses; any other value is bound as a
+// placeholder. The rows are written to w as they are rendered, so large
+// multi-row inserts do not build intermediate strings.
+func (v valuesList) AppendToSql(w io.Writer, args []interface{}) ([]interface{}, error) {
+	if len(v) == 0 {
+		return args, buildErrorf(CodeNoValues, "values list must have at least one row")
+	}
+
+	if n := len(v) * len(v[0]); cap(args)-len(args) < n {
+		grown := make([]interface{}, len(args), len(args)+n)
+		copy(grown, args)
+		args = grown
+	}
+
+	io.WriteString(w, "VALUES ")
+	for r, row := range v {
+		if r > 0 {
+			io.WriteString(w, ",")
+		}
+		io.WriteString(w, "(")
+		for i, val := range row {
+			if i > 0 {
+				io.WriteString(w, ",")
+			}
+
+			switch typedVal := val.(type) {
+			case expr:
+				valSql, valArgs, err := typedVal.ToSql()
+				if err != nil {
+					return nil, err
+				}
+
+				io.WriteString(w, valSql)
+				args = append(args, valArgs...)
+			case Sqlizer:
+				valSql, valArgs, err := nestedToSql(typedVal)
+				if err != nil {
+					return nil, err
+				}
+				if valSql == "" {
+					return nil, buildErrorf(CodeNoValues, "values row %d has an empty value", r+1)
+				}
+
+				if _, ok := typedVal.(*SelectBuilder); ok {
+					valSql = "(" + valSql + ")"
+				}
+				io.WriteString(w, valSql)
+				args = append(args, valArgs...)
+			default:
+				io.WriteString(w, "?")
+				args = append(args, val)
+			}
+		}
+		io.WriteString(w, ")")
+	}
+
+	return args, nil
+}
@@ -18,12 +18,11 @@ func (p wherePart) ToSql() (sql string, args []interface{}, err error) {
 	case nil:
 		// no-op
 	case Sqlizer:
-		return pred.ToSql()
+		return nestedToSql(pred)
 	case map[string]interface{}:
 		return Eq(pred).ToSql()
 	case string:
-		sql = pred
-		args = p.args
+		return Expr(pred, p.args...).ToSql()
 	default:
 		err = fmt.Errorf("expected string-keyed map or string, not %T", pred)
 	}
@@ -45,13 +44,17 @@ type WhereBuilder struct {
 	offsetValid bool
 }
 
+// NewWhereBuilder creates new instance of WhereBuilder
+func NewWhereBuilder(b StatementBuilderType) *WhereBuilder {
+	return &WhereBuilder{StatementBuilderType: b}
+}
+
 // ToSql builds the query into a SQL string and bound args.
 func (b *WhereBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 	sql := &bytes.Buffer{}
 
 	if len(b.whereParts) > 0 {
-		sql.WriteString(" WHERE ")
-		args, err = appendToSql(b.whereParts, sql, " AND ", args)
+		args, err = appendClauseToSql(b.whereParts, sql, " WHERE ", " AND ", args)
 		if err != nil {
 			return
 		}
@@ -63,8 +66,7 @@ func (b *WhereBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 	}
 
 	if len(b.havingParts) > 0 {
-		sql.WriteString(" HAVING ")
-		args, err = appendToSql(b.havingParts, sql, " AND ", args)
+		args, err = appendClauseToSql(b.havingParts, sql, " HAVING ", " AND ", args)
 		if err != nil {
 			return
 		}
@@ -75,48 +77,148 @@ func (b *WhereBuilder) ToSql() (sqlStr string, args []interface{}, err error) {
 		sql.WriteString(strings.Join(b.orderBys, ", "))
 	}
 
-	// TODO: limit == 0 and offswt == 0 are valid. Need to go dbr way and implement offsetValid and limitValid
-	if b.limitValid {
-		sql.WriteString(" LIMIT ")
-		sql.WriteString(strconv.FormatUint(b.limit, 10))
-	}
+	if b.standardLimit {
+		appendStandardLimit(sql, b.limit, b.limitValid, b.offset, b.offsetValid)
+	} else {
+		if b.limitValid {
+			sql.WriteString(" LIMIT ")
+			sql.WriteString(strconv.FormatUint(b.limit, 10))
+		}
 
-	if b.offsetValid {
-		sql.WriteString(" OFFSET ")
-		sql.WriteString(strconv.FormatUint(b.offset, 10))
+		if b.offsetValid {
+			sql.WriteString(" OFFSET ")
+			sql.WriteString(strconv.FormatUint(b.offset, 10))
+		}
 	}
 
-	sqlStr, err = b.placeholderFormat.ReplacePlaceholders(sql.String())
+	if err = checkArgRefs(sql.String(), args); err != nil {
+		return
+	}
+	sqlStr, err = b.format().ReplacePlaceholders(sql.String())
 	return
 
 }
 
+// WhereToSql builds only the WHERE clause of the query, " WHERE ...", and
+// its args, leaving out GROUP BY, HAVING, ORDER BY and the limits, so the
+// same filter can be spliced into hand-written statements. It returns an
+// empty string and nil args if there are no WHERE expressions.
+func (b *WhereBuilder) WhereToSql() (sqlStr string, args []interface{}, err error) {
+	if len(b.whereParts) == 0 {
+		return
+	}
+
+	sql := &bytes.Buffer{}
+	args, err = appendClauseToSql(b.whereParts, sql, " WHERE ", " AND ", args)
+	if err != nil {
+		return
+	}
+
+	if err = checkArgRefs(sql.String(), args); err != nil {
+		return
+	}
+	sqlStr, err = b.format().ReplacePlaceholders(sql.String())
+	return
+}
+
 // Where will panic if pred isn't any of the above types.
 func (b *WhereBuilder) Where(pred interface{}, args ...interface{}) *WhereBuilder {
 	b.whereParts = append(b.whereParts, newWherePart(pred, args...))
 	return b
 }
 
+// WhereIf adds a WHERE expression to the query when cond is true and is
+// a no-op otherwise.
+func (b *WhereBuilder) WhereIf(cond bool, pred interface{}, args ...interface{}) *WhereBuilder {
+	if !cond {
+		return b
+	}
+	return b.Where(pred, args...)
+}
+
+// MergeWhere appends the WHERE, GROUP BY and HAVING parts of other to b,
+// after the parts already in b, so shared filters can be kept in one
+// WhereBuilder and merged into several others. other is not modified.
+// Ex:
+//     tenant := NewWhereBuilder(StatementBuilder).Where(Eq{"tenant_id": id})
+//     NewWhereBuilder(StatementBuilder).Where(Eq{"active": true}).MergeWhere(tenant)
+//     == " WHERE active = ? AND tenant_id = ?"
+func (b *WhereBuilder) MergeWhere(other *WhereBuilder) *WhereBuilder {
+	if other == nil {
+		return b
+	}
+	b.whereParts = append(b.whereParts, other.whereParts...)
+	b.groupBys = append(b.groupBys, other.groupBys...)
+	b.havingParts = append(b.havingParts, other.havingParts...)
+	return b
+}
+
 // GroupBy adds GROUP BY expressions to the query.
 func (b *WhereBuilder) GroupBy(groupBys ...string) *WhereBuilder {
 	b.groupBys = append(b.groupBys, groupBys...)
 	return b
 }
 
+// GroupByRollup adds "ROLLUP(cols)" to the GROUP BY clause of the query. It
+// can be combined with plain GroupBy columns.
+func (b *WhereBuilder) GroupByRollup(cols ...string) *WhereBuilder {
+	return b.GroupBy(rollup(cols))
+}
+
+// GroupByCube adds "CUBE(cols)" to the GROUP BY clause of the query.
+func (b *WhereBuilder) GroupByCube(cols ...string) *WhereBuilder {
+	return b.GroupBy(cube(cols))
+}
+
+// GroupBySets adds "GROUPING SETS ((a, b), (c), ())" to the GROUP BY clause
+// of the query, one parenthesized set per argument. An empty set groups the
+// grand total.
+func (b *WhereBuilder) GroupBySets(sets ...[]string) *WhereBuilder {
+	return b.GroupBy(groupingSets(sets))
+}
+
 // Having adds an expression to the HAVING clause of the query.
 //
-// See Where.
+// It accepts the same predicates as Where, so comparison helpers work on
+// aggregates, e.g. Having(Gt{"COUNT(*)": 5}) renders "HAVING COUNT(*) > ?".
 func (b *WhereBuilder) Having(pred interface{}, rest ...interface{}) *WhereBuilder {
 	b.havingParts = append(b.havingParts, newWherePart(pred, rest...))
 	return b
 }
 
+// HavingIf adds an expression to the HAVING clause of the query when cond is
+// true and is a no-op otherwise.
+func (b *WhereBuilder) HavingIf(cond bool, pred interface{}, rest ...interface{}) *WhereBuilder {
+	if !cond {
+		return b
+	}
+	return b.Having(pred, rest...)
+}
+
 // OrderBy adds ORDER BY expressions to the query.
 func (b *WhereBuilder) OrderBy(orderBys ...string) *WhereBuilder {
 	b.orderBys = append(b.orderBys, orderBys...)
 	return b
 }
 
+// OrderByIf adds ORDER BY expressions to the query when cond is true and is
+// a no-op otherwise.
+func (b *WhereBuilder) OrderByIf(cond bool, orderBys ...string) *WhereBuilder {
+	if !cond {
+		return b
+	}
+	return b.OrderBy(orderBys...)
+}
+
+// StandardLimitSyntax makes ToSql render Limit and Offset as
+// "OFFSET n ROWS FETCH NEXT m ROWS ONLY".
+//
+// See SelectBuilder.StandardLimitSyntax.
+func (b *WhereBuilder) StandardLimitSyntax(standard bool) *WhereBuilder {
+	b.standardLimit = standard
+	return b
+}
+
 // Limit sets a LIMIT clause on the query.
 func (b *WhereBuilder) Limit(limit uint64) *WhereBuilder {
 	b.limit = limit
@@ -124,9 +226,33 @@ func (b *WhereBuilder) Limit(limit uint64) *WhereBuilder {
 	return b
 }
 
+// LimitIf adds a LIMIT clause to the query when cond is true and is
+// a no-op otherwise.
+func (b *WhereBuilder) LimitIf(cond bool, limit uint64) *WhereBuilder {
+	if !cond {
+		return b
+	}
+	return b.Limit(limit)
+}
+
 // Offset sets a OFFSET clause on the query.
 func (b *WhereBuilder) Offset(offset uint64) *WhereBuilder {
 	b.offset = offset
 	b.offsetValid = true
 	return b
 }
+
+// ClearLimit removes the LIMIT clause set by Limit. Unlike Limit(0), which
+// renders "LIMIT 0", it leaves the query without a LIMIT clause.
+func (b *WhereBuilder) ClearLimit() *WhereBuilder {
+	b.limit = 0
+	b.limitValid = false
+	return b
+}
+
+// ClearOffset removes the OFFSET clause set by Offset.
+func (b *WhereBuilder) ClearOffset() *WhereBuilder {
+	b.offset = 0
+	b.offsetValid = false
+	return b
+}
@@ -0,0 +1,133 @@
+package bsql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWhereBuilderOr(t *testing.T) {
+	b := NewWhereBuilder(StatementBuilder).Where(Or{Eq{"a": 1}, Eq{"b": 2}})
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := " WHERE (a = ? OR b = ?)"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{1, 2}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestWhereBuilderNestedAndOr(t *testing.T) {
+	b := NewWhereBuilder(StatementBuilder).
+		Where(Or{
+			And{Eq{"a": 1}, Eq{"b": 2}},
+			And{Eq{"c": 3}, Or{Eq{"d": 4}, Eq{"e": 5}}},
+		}).
+		Where("f = ?", 6)
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := " WHERE ((a = ? AND b = ?) OR (c = ? AND (d = ? OR e = ?))) AND f = ?"
+	assert.Equal(t, expectedSql, sql)
+
+	expectedArgs := []interface{}{1, 2, 3, 4, 5, 6}
+	assert.Equal(t, expectedArgs, args)
+}
+
+func TestWhereBuilderGroupBySets(t *testing.T) {
+	sql, _, err := NewWhereBuilder(StatementBuilder).GroupBySets([]string{"a"}, []string{}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, " GROUP BY GROUPING SETS ((a), ())", sql)
+}
+
+func TestWhereBuilderWhereIf(t *testing.T) {
+	sql, args, err := NewWhereBuilder(StatementBuilder).
+		WhereIf(false, Eq{"a": 1}).
+		WhereIf(true, Eq{"b": 2}).
+		LimitIf(true, 3).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, " WHERE b = ? LIMIT 3", sql)
+	assert.Equal(t, []interface{}{2}, args)
+}
+
+func TestWhereBuilderMergeWhere(t *testing.T) {
+	tenant := NewWhereBuilder(StatementBuilder).Where(Eq{"tenant_id": 9}).Having("COUNT(*) > ?", 1)
+	sql, args, err := NewWhereBuilder(StatementBuilder).
+		Where(Eq{"active": true}).
+		GroupBy("team").
+		MergeWhere(tenant).
+		MergeWhere(nil).
+		Where("created_at > ?", "2020-01-01").
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, " WHERE active = ? AND tenant_id = ? AND created_at > ? GROUP BY team HAVING COUNT(*) > ?", sql)
+	assert.Equal(t, []interface{}{true, 9, "2020-01-01", 1}, args)
+
+	sql, args, err = tenant.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, " WHERE tenant_id = ? HAVING COUNT(*) > ?", sql)
+	assert.Equal(t, []interface{}{9, 1}, args)
+}
+
+func TestWhereBuilderWhereToSql(t *testing.T) {
+	b := NewWhereBuilder(StatementBuilder).
+		Where(Eq{"tenant_id": 9}).
+		Where("created_at > ?", "2020-01-01").
+		GroupBy("team").
+		OrderBy("team").
+		Limit(10)
+	sql, args, err := b.WhereToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, " WHERE tenant_id = ? AND created_at > ?", sql)
+	assert.Equal(t, []interface{}{9, "2020-01-01"}, args)
+
+	sql, args, err = NewWhereBuilder(StatementBuilder).OrderBy("team").WhereToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "", sql)
+	assert.Nil(t, args)
+}
+
+func TestWhereBuilderStandardLimitSyntax(t *testing.T) {
+	b := NewWhereBuilder(StatementBuilder).Where(Eq{"a": 1}).OrderBy("id").StandardLimitSyntax(true)
+
+	sql, _, err := b.Limit(10).Offset(20).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, " WHERE a = ? ORDER BY id OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY", sql)
+
+	sql, _, err = b.ClearOffset().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, " WHERE a = ? ORDER BY id FETCH FIRST 10 ROWS ONLY", sql)
+
+	sql, _, err = b.ClearLimit().Offset(20).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, " WHERE a = ? ORDER BY id OFFSET 20 ROWS", sql)
+
+	sql, _, err = StatementBuilder.StandardLimitSyntax(true).Select("id").From("t").OrderBy("id").Limit(5).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM t ORDER BY id FETCH FIRST 5 ROWS ONLY", sql)
+}
+
+func TestWhereBuilderEmptyPredicates(t *testing.T) {
+	sql, args, err := NewWhereBuilder(StatementBuilder).
+		Where("").
+		Where("a = ?", 1).
+		Where(nil).
+		Where("").
+		Where(Eq{"b": 2}).
+		Having("").
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, " WHERE a = ? AND b = ?", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+
+	sql, args, err = NewWhereBuilder(StatementBuilder).Where("").Where(And{}).WhereToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "", sql)
+	assert.Nil(t, args)
+
+	sql, _, err = Select("*").From("t").Where("").Where("x = ?", 1).Where("").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE x = ?", sql)
+}
@@ -0,0 +1,82 @@
+package bsql
+
+import (
+	"fmt"
+	"io"
+)
+
+// namedWindow is a window definition of the WINDOW clause, e.g.
+// "w AS (PARTITION BY dept ORDER BY salary)".
+type namedWindow struct {
+	name string
+	spec string
+}
+
+func appendWindowsToSql(windows []namedWindow, w io.Writer) {
+	io.WriteString(w, " WINDOW ")
+	for i, nw := range windows {
+		if i > 0 {
+			io.WriteString(w, ", ")
+		}
+		fmt.Fprintf(w, "%s AS (%s)", nw.name, nw.spec)
+	}
+}
+
+// over is a window function call over a named window.
+type over struct {
+	fn     string
+	window string
+}
+
+// Over renders "fn OVER window", calling a window function over a window
+// defined with SelectBuilder.Window. Several columns can share one window.
+// Ex:
+//     .Column(Over("rank()", "w")).Window("w", "PARTITION BY dept ORDER BY salary DESC")
+func Over(fn, window string) over {
+	return over{fn: fn, window: window}
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (o over) ToSql() (sql string, args []interface{}, err error) {
+	sql = fmt.Sprintf("%s OVER %s", o.fn, o.window)
+	return
+}
+
+// checkWindows returns an error if a column calls Over with a window that is
+// not defined in windows.
+func checkWindows(columns []Sqlizer, windows []namedWindow) error {
+	for _, c := range columns {
+		o, ok := columnOver(c)
+		if !ok {
+			continue
+		}
+
+		defined := false
+		for _, nw := range windows {
+			if nw.name == o.window {
+				defined = true
+				break
+			}
+		}
+		if !defined {
+			return buildErrorf(CodeInvalidClause, "window %s is not defined", o.window)
+		}
+	}
+	return nil
+}
+
+// columnOver returns the Over call of a result column, looking through
+// Column and Alias wrappers.
+func columnOver(s Sqlizer) (over, bool) {
+	switch c := s.(type) {
+	case over:
+		return c, true
+	case *part:
+		if pred, ok := c.pred.(Sqlizer); ok {
+			return columnOver(pred)
+		}
+	case aliasExpr:
+		return columnOver(c.expr)
+	}
+	return over{}, false
+}
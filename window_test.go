@@ -0,0 +1,31 @@
+package bsql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectBuilderWindow(t *testing.T) {
+	b := Select("name", "dept").
+		Column(Over("rank()", "w")).
+		Column(Alias(Over("sum(salary)", "w"), "running_total")).
+		From("employees").
+		Where(Eq{"active": true}).
+		Window("w", "PARTITION BY dept ORDER BY salary DESC").
+		OrderBy("dept")
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT name, dept, rank() OVER w, (sum(salary) OVER w) AS running_total " +
+		"FROM employees WHERE active = ? " +
+		"WINDOW w AS (PARTITION BY dept ORDER BY salary DESC) ORDER BY dept"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{true}, args)
+}
+
+func TestSelectBuilderWindowUndefined(t *testing.T) {
+	_, _, err := Select("name").Column(Over("rank()", "w2")).From("employees").
+		Window("w", "ORDER BY salary").ToSql()
+	assert.EqualError(t, err, "window w2 is not defined")
+}